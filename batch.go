@@ -0,0 +1,152 @@
+package ingredients
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Source is a recipe to parse with ParseBatch: a url, a file, HTML or a
+// reader of HTML. Name is the FileName of recipes from HTML or a reader,
+// "html" if it is empty.
+type Source struct {
+	URL    string    `json:"url,omitempty"`
+	File   string    `json:"file,omitempty"`
+	Name   string    `json:"name,omitempty"`
+	HTML   string    `json:"-"`
+	Reader io.Reader `json:"-"`
+}
+
+// URLSource is a recipe fetched from a url
+func URLSource(url string) Source {
+	return Source{URL: url}
+}
+
+// FileSource is a recipe read from a file
+func FileSource(fname string) Source {
+	return Source{File: fname}
+}
+
+// HTMLSource is a recipe parsed from HTML
+func HTMLSource(name, htmlString string) Source {
+	return Source{Name: name, HTML: htmlString}
+}
+
+// ReaderSource is a recipe parsed from HTML read from a reader
+func ReaderSource(name string, r io.Reader) Source {
+	return Source{Name: name, Reader: r}
+}
+
+// String returns the url, file or name of the source
+func (s Source) String() string {
+	switch {
+	case s.URL != "":
+		return s.URL
+	case s.File != "":
+		return s.File
+	}
+	return s.Name
+}
+
+// htmlName is the FileName of a recipe from HTML or a reader
+func (s Source) htmlName() string {
+	if s.Name == "" {
+		return "html"
+	}
+	return s.Name
+}
+
+// BatchOptions sets how ParseBatch parses its sources
+type BatchOptions struct {
+	// Workers is how many sources are parsed at once, the number of CPUs
+	// if it is zero
+	Workers int
+	// Timeout is the longest each url can take to fetch and parse, no
+	// limit if it is zero. Files, HTML and readers are not timed, since
+	// they cannot be interrupted once they start.
+	Timeout time.Duration
+}
+
+// BatchResult is the recipe parsed from a source, or the error that
+// stopped it. Index is the position of the source in the batch.
+type BatchResult struct {
+	Index  int
+	Source Source
+	Recipe *Recipe
+	Err    error
+}
+
+// ParseBatch parses many recipes at once
+func ParseBatch(ctx context.Context, sources []Source, opts BatchOptions) <-chan BatchResult {
+	return defaultParser.ParseBatch(ctx, sources, opts)
+}
+
+// ParseBatch parses sources with a pool of workers, and sends a result for
+// each of them, in the order they finish. The channel is closed after the
+// last result, and must be read until then. Once the context is done the
+// sources that are left fail with its error.
+func (p *Parser) ParseBatch(ctx context.Context, sources []Source, opts BatchOptions) <-chan BatchResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(sources) {
+		workers = len(sources)
+	}
+
+	jobs := make(chan int)
+	results := make(chan BatchResult, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r, err := p.parseSource(ctx, sources[i], opts.Timeout)
+				results <- BatchResult{Index: i, Source: sources[i], Recipe: r, Err: err}
+			}
+		}()
+	}
+	go func() {
+		for i := range sources {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// parseSource parses a source within a timeout. Only fetching a url can
+// be interrupted, others only check the context before they start.
+func (p *Parser) parseSource(ctx context.Context, s Source, timeout time.Duration) (r *Recipe, err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case s.URL != "":
+		return p.NewFromURLWithContext(ctx, s.URL)
+	case s.File != "":
+		r, err = p.NewFromFile(s.File)
+	case s.Reader != nil:
+		var b []byte
+		if b, err = io.ReadAll(s.Reader); err != nil {
+			return nil, err
+		}
+		r, err = p.NewFromHTML(s.htmlName(), string(b))
+	case s.HTML != "":
+		r, err = p.NewFromHTML(s.htmlName(), s.HTML)
+	default:
+		return nil, ErrEmptySource
+	}
+	return
+}
@@ -0,0 +1,142 @@
+package ingredients
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const batchTestHTML = "<ul><li>1 cup flour</li><li>2 eggs</li></ul>"
+
+func collect(results <-chan BatchResult, n int) []BatchResult {
+	byIndex := make([]BatchResult, n)
+	for result := range results {
+		byIndex[result.Index] = result
+	}
+	return byIndex
+}
+
+func TestParseBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte(batchTestHTML))
+	}))
+	defer server.Close()
+
+	sources := []Source{
+		FileSource(fetchTestPage),
+		HTMLSource("html", batchTestHTML),
+		ReaderSource("reader", strings.NewReader(batchTestHTML)),
+		URLSource(server.URL),
+		URLSource(server.URL + "/slow"),
+		FileSource("testing/does-not-exist.html"),
+		{},
+	}
+	results := collect(ParseBatch(context.Background(), sources, BatchOptions{Workers: 3, Timeout: 100 * time.Millisecond}), len(sources))
+	for i, result := range results[:4] {
+		assert.Nil(t, result.Err, i)
+		assert.Equal(t, sources[i], result.Source)
+		assert.NotEmpty(t, result.Recipe.Ingredients, i)
+	}
+	assert.Equal(t, "reader", results[2].Recipe.FileName)
+	assert.True(t, errors.Is(results[4].Err, context.DeadlineExceeded))
+	assert.True(t, errors.Is(results[5].Err, fs.ErrNotExist))
+	assert.True(t, errors.Is(results[6].Err, ErrEmptySource))
+	assert.Equal(t, server.URL+"/slow", results[4].Source.String())
+}
+
+func TestParseBatchUnnamed(t *testing.T) {
+	sources := []Source{
+		HTMLSource("", batchTestHTML),
+		ReaderSource("", strings.NewReader(batchTestHTML)),
+		{HTML: batchTestHTML},
+	}
+	for _, result := range collect(ParseBatch(context.Background(), sources, BatchOptions{}), len(sources)) {
+		assert.Nil(t, result.Err, result.Index)
+		assert.Equal(t, "html", result.Recipe.FileName, result.Index)
+		assert.NotEmpty(t, result.Recipe.Ingredients, result.Index)
+	}
+}
+
+func TestParseBatchCancel(t *testing.T) {
+	started := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-req.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	sources := make([]Source, 10)
+	for i := range sources {
+		sources[i] = URLSource(server.URL)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	results := NewParser().ParseBatch(ctx, sources, BatchOptions{Workers: 2})
+	<-started
+	cancel()
+	start := time.Now()
+	n := 0
+	for result := range results {
+		assert.True(t, errors.Is(result.Err, context.Canceled))
+		n++
+	}
+	assert.Equal(t, len(sources), n)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// an empty batch is closed at once
+	_, ok := <-ParseBatch(context.Background(), nil, BatchOptions{})
+	assert.False(t, ok)
+}
+
+// TestParseBatchConcurrent parses the same pages at once with one Parser.
+// Run it with -race to check that a Parser can be shared.
+func TestParseBatchConcurrent(t *testing.T) {
+	var files []string
+	filepath.WalkDir("testing/sites", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Name() == "index.html" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	assert.NotEmpty(t, files)
+
+	p := NewParser(WithFuzzyMatching(0.8), WithCorpusAdditions(Corpus{Ingredients: []string{"wattleseed"}}))
+	want := make(map[string]string)
+	var sources []Source
+	for _, file := range files {
+		r, err := p.NewFromFile(file)
+		if err != nil {
+			continue
+		}
+		want[file] = r.IngredientList().String()
+		b, err := os.ReadFile(file)
+		assert.Nil(t, err)
+		for i := 0; i < 4; i++ {
+			sources = append(sources, FileSource(file), HTMLSource(file, string(b)))
+		}
+	}
+
+	for result := range p.ParseBatch(context.Background(), sources, BatchOptions{Workers: 8}) {
+		assert.Nil(t, result.Err)
+		name := result.Source.String()
+		assert.Equal(t, want[name], result.Recipe.IngredientList().String(), name)
+		// recipes can be changed while others are parsed
+		_, err := result.Recipe.Scale(2)
+		assert.Nil(t, err)
+		assert.Nil(t, result.Recipe.ConvertTo(Metric))
+	}
+}
@@ -0,0 +1,90 @@
+package ingredients
+
+// Category is the kind of an ingredient, e.g. for sorting a grocery list
+// by aisle. Parents in the corpus can name a category, like "cheese" whose
+// parent is "dairy".
+type Category string
+
+// The categories of ingredients
+const (
+	CategoryHerbSpice Category = "herb_spice"
+	CategoryFruit     Category = "fruit"
+	CategoryVegetable Category = "vegetable"
+	CategoryProtein   Category = "protein"
+	CategoryDairy     Category = "dairy"
+	CategoryGrain     Category = "grain"
+	CategorySweetener Category = "sweetener"
+	CategoryFat       Category = "fat"
+	CategoryLiquid    Category = "liquid"
+	CategoryOther     Category = "other"
+)
+
+// categoryOrder is the order of the categories in a breakdown, and the
+// precedence of the categories when an ingredient is in several
+var categoryOrder = []Category{
+	CategoryHerbSpice,
+	CategoryFruit,
+	CategoryVegetable,
+	CategoryProtein,
+	CategoryDairy,
+	CategoryGrain,
+	CategorySweetener,
+	CategoryFat,
+	CategoryLiquid,
+	CategoryOther,
+}
+
+// CategoryBreakdown is the part of a recipe in one category. Weight is the
+// grams of its ingredients that could be weighed and Share is the fraction
+// of the weight of the recipe.
+type CategoryBreakdown struct {
+	Category    Category     `json:"category"`
+	Ingredients []Ingredient `json:"ingredients"`
+	Weight      float64      `json:"weight"`
+	Share       float64      `json:"share"`
+}
+
+// category returns the category of an ingredient, falling back to its
+// synonyms and then up its parents
+func (p *Parser) category(name string) Category {
+	for _, id := range append([]string{name}, p.lineage(name)...) {
+		for _, category := range categoryOrder {
+			if id == string(category) {
+				return category
+			}
+			if _, ok := p.categories[category][id]; ok {
+				return category
+			}
+		}
+	}
+	return CategoryOther
+}
+
+// ByCategory breaks the ingredients of the recipe down by category, in the
+// order herbs and spices, fruits, vegetables, proteins, dairy, grains,
+// sweeteners, fats, liquids and others. Empty categories are left out.
+func (r *Recipe) ByCategory() (breakdown []CategoryBreakdown) {
+	byCategory := make(map[Category]*CategoryBreakdown)
+	total := 0.0
+	for _, ing := range r.Ingredients {
+		category := ing.Category
+		if category == "" {
+			category = CategoryOther
+		}
+		if _, ok := byCategory[category]; !ok {
+			byCategory[category] = &CategoryBreakdown{Category: category}
+		}
+		byCategory[category].Ingredients = append(byCategory[category].Ingredients, ing)
+		byCategory[category].Weight += ing.Measure.Weight
+		total += ing.Measure.Weight
+	}
+	for _, category := range categoryOrder {
+		if b, ok := byCategory[category]; ok {
+			if total > 0 {
+				b.Share = b.Weight / total
+			}
+			breakdown = append(breakdown, *b)
+		}
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package ingredients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		category Category
+	}{
+		{"basil", CategoryHerbSpice},
+		{"cinnamon", CategoryHerbSpice},
+		{"apple", CategoryFruit},
+		{"carrot", CategoryVegetable},
+		{"chicken", CategoryProtein},
+		{"egg", CategoryProtein},
+		{"milk", CategoryDairy},
+		{"flour", CategoryGrain},
+		{"honey", CategorySweetener},
+		{"butter", CategoryFat},
+		{"olive oil", CategoryFat},
+		{"water", CategoryLiquid},
+		{"baking soda", CategoryOther},
+		// synonyms and parents
+		{"courgette", CategoryVegetable},
+		{"aged cheddar", CategoryDairy},
+		{"icing sugar", CategorySweetener},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.category, defaultParser.category(test.name), test.name)
+	}
+
+	p := NewParser(WithCorpusAdditions(Corpus{Proteins: []string{"kangaroo"}}))
+	assert.Equal(t, CategoryProtein, p.category("kangaroo"))
+	assert.Equal(t, CategoryOther, defaultParser.category("kangaroo"))
+}
+
+func TestByCategory(t *testing.T) {
+	r := parseLines(t, `2 cups flour
+1 cup sugar
+1/2 cup brown sugar
+1 cup butter
+2 eggs
+1 teaspoon salt`)
+	assert.Equal(t, CategoryGrain, r.Ingredients[0].Category)
+
+	breakdown := r.ByCategory()
+	categories := make([]Category, len(breakdown))
+	share := 0.0
+	for i, b := range breakdown {
+		categories[i] = b.Category
+		share += b.Share
+	}
+	assert.Equal(t, []Category{CategoryHerbSpice, CategoryProtein, CategoryGrain, CategorySweetener, CategoryFat}, categories)
+	assert.Equal(t, 2, len(breakdown[3].Ingredients))
+	assert.Equal(t, 100.0, breakdown[1].Weight)
+	assert.InDelta(t, 1, share, 1e-9)
+}
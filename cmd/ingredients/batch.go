@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jasonstubblefield/ingredients"
+	log "github.com/schollz/logger"
+	"github.com/schollz/progressbar/v3"
+)
+
+// BatchResult is a line of the output of batch mode, with the ingredients
+// of a source or the error that stopped it
+type BatchResult struct {
+	Origin      string                   `json:"origin"`
+	Ingredients []ingredients.Ingredient `json:"ingredients,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+// runBatch parses the files and urls listed in a file, or in stdin for
+// "-", and writes a line of JSON for each of them. It returns the exit
+// code, 1 if any of them failed.
+func runBatch(arguments []string) int {
+	flags := flag.NewFlagSet("batch", flag.ExitOnError)
+	workers := flags.Int("j", runtime.NumCPU(), "number of recipes to parse at once")
+	outputFile := flags.String("o", "", "write the results to a file instead of stdout")
+	timeout := flags.Duration("timeout", 30*time.Second, "longest time for each recipe")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: ingredients batch [-j workers] [-o output.ndjson] [-timeout 30s] [urls.txt or -]")
+		flags.PrintDefaults()
+	}
+	// flags can come after the list, as in "ingredients batch urls.txt -j 8"
+	var positional []string
+	flags.Parse(arguments)
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		flags.Parse(flags.Args()[1:])
+	}
+	if len(positional) != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	sources, err := readSources(positional[0])
+	if err != nil {
+		log.Errorf("failed to read %s: %v", positional[0], err)
+		return 1
+	}
+
+	var out io.Writer = os.Stdout
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			log.Errorf("failed to create output file: %v", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+	encoder := json.NewEncoder(w)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	p := ingredients.NewParser(
+		ingredients.WithRetries(ingredients.DefaultRetryPolicy),
+		ingredients.WithRateLimit(2, 4),
+	)
+	bar := progressbar.NewOptions(len(sources),
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionSetDescription("parsing"),
+		progressbar.OptionShowCount(),
+	)
+
+	var failures []BatchResult
+	for result := range p.ParseBatch(ctx, sources, ingredients.BatchOptions{Workers: *workers, Timeout: *timeout}) {
+		line := BatchResult{Origin: result.Source.String()}
+		if result.Err != nil {
+			line.Error = result.Err.Error()
+			failures = append(failures, line)
+		} else {
+			line.Ingredients = result.Recipe.IngredientList().Ingredients
+		}
+		if err := encoder.Encode(line); err != nil {
+			log.Errorf("failed to write result: %v", err)
+			return 1
+		}
+		// keep stdout in step with the progress bar
+		if *outputFile == "" {
+			w.Flush()
+		}
+		bar.Add(1)
+	}
+	bar.Finish()
+	fmt.Fprintln(os.Stderr)
+
+	fmt.Fprintf(os.Stderr, "%d parsed, %d failed\n", len(sources)-len(failures), len(failures))
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", failure.Origin, failure.Error)
+	}
+	if len(failures) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// readSources reads a file, or stdin for "-", with a file or url on each
+// line. Lines without a scheme like "https://" are files. Blank lines and
+// lines starting with # are skipped.
+func readSources(name string) (sources []ingredients.Source, err error) {
+	in := os.Stdin
+	if name != "-" {
+		if in, err = os.Open(name); err != nil {
+			return
+		}
+		defer in.Close()
+	}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if u, parseErr := url.Parse(line); parseErr == nil && u.Scheme != "" && u.Host != "" {
+			sources = append(sources, ingredients.URLSource(line))
+		} else {
+			sources = append(sources, ingredients.FileSource(line))
+		}
+	}
+	err = scanner.Err()
+	return
+}
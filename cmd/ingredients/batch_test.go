@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jasonstubblefield/ingredients"
+	"github.com/stretchr/testify/assert"
+)
+
+const batchTestPage = "../../testing/sites/joyfoodsunshine.com/the-most-amazing-chocolate-chip-cookies/index.html"
+
+// writeFile writes a file in a temporary directory of the test
+func writeFile(t *testing.T, name, content string) string {
+	fname := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(fname, []byte(content), 0644))
+	return fname
+}
+
+// readResults reads the lines of JSON written by runBatch
+func readResults(t *testing.T, fname string) (results []BatchResult) {
+	f, err := os.Open(fname)
+	assert.Nil(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var result BatchResult
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &result), scanner.Text())
+		results = append(results, result)
+	}
+	assert.Nil(t, scanner.Err())
+	return
+}
+
+func TestReadSources(t *testing.T) {
+	list := writeFile(t, "urls.txt", `# recipes
+https://example.com/recipe
+
+recipe.html
+testing/sites/recipe.html
+C:\recipes\recipe.html
+`)
+	sources, err := readSources(list)
+	assert.Nil(t, err)
+	assert.Equal(t, []ingredients.Source{
+		ingredients.URLSource("https://example.com/recipe"),
+		ingredients.FileSource("recipe.html"),
+		ingredients.FileSource("testing/sites/recipe.html"),
+		ingredients.FileSource(`C:\recipes\recipe.html`),
+	}, sources)
+
+	_, err = readSources(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.NotNil(t, err)
+}
+
+func TestRunBatch(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "recipes.ndjson")
+	list := writeFile(t, "urls.txt", batchTestPage+"\n")
+	assert.Equal(t, 0, runBatch([]string{list, "-j", "2", "-o", output}))
+	results := readResults(t, output)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, batchTestPage, results[0].Origin)
+	assert.NotEmpty(t, results[0].Ingredients)
+	assert.Empty(t, results[0].Error)
+
+	// a failure is a line with an error, and an exit code of 1
+	list = writeFile(t, "urls.txt", batchTestPage+"\nmissing.html\n")
+	assert.Equal(t, 1, runBatch([]string{"-o", output, list}))
+	results = readResults(t, output)
+	assert.Equal(t, 2, len(results))
+	for _, result := range results {
+		if result.Origin == "missing.html" {
+			assert.NotEmpty(t, result.Error)
+			assert.Empty(t, result.Ingredients)
+		} else {
+			assert.Equal(t, batchTestPage, result.Origin)
+			assert.NotEmpty(t, result.Ingredients)
+		}
+	}
+
+	// the list is required
+	assert.Equal(t, 2, runBatch(nil))
+	assert.Equal(t, 1, runBatch([]string{filepath.Join(t.TempDir(), "missing.txt")}))
+}
+
+func TestRunBatchStdin(t *testing.T) {
+	stdin, err := os.Open(writeFile(t, "urls.txt", strings.Repeat(batchTestPage+"\n", 3)))
+	assert.Nil(t, err)
+	defer stdin.Close()
+	original := os.Stdin
+	os.Stdin = stdin
+	t.Cleanup(func() { os.Stdin = original })
+
+	output := filepath.Join(t.TempDir(), "recipes.ndjson")
+	assert.Equal(t, 0, runBatch([]string{"-", "-o", output}))
+	results := readResults(t, output)
+	assert.Equal(t, 3, len(results))
+	for _, result := range results {
+		assert.NotEmpty(t, result.Ingredients)
+	}
+}
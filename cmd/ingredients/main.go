@@ -29,6 +29,10 @@ func getCacheDir() (string, error) {
 func main() {
 	log.SetLevel("error")
 
+	if len(os.Args) > 1 && os.Args[1] == "batch" {
+		os.Exit(runBatch(os.Args[2:]))
+	}
+
 	// Check for -stdin mode early (before flag parsing)
 	isStdinMode := false
 	for _, arg := range os.Args[1:] {
@@ -40,12 +44,15 @@ func main() {
 
 	// For stdin mode, manually extract arguments to avoid flag parsing issues
 	var outputFile *string
+	var explain *bool
 	var args []string
 
 	if isStdinMode {
 		// Parse manually for stdin mode
 		outputFileVal := ""
 		outputFile = &outputFileVal
+		explainVal := false
+		explain = &explainVal
 		skipNext := false
 		for i := 1; i < len(os.Args); i++ {
 			if skipNext {
@@ -56,12 +63,14 @@ func main() {
 			if arg == "-o" && i+1 < len(os.Args) {
 				*outputFile = os.Args[i+1]
 				skipNext = true
+			} else if arg == "-explain" || arg == "--explain" {
+				*explain = true
 			} else {
 				args = append(args, arg)
 			}
 		}
 		if len(args) < 1 {
-			log.Error("usage: ingredients -stdin [-o output.json]")
+			log.Error("usage: ingredients -stdin [-o output.json] [--explain]")
 			os.Exit(1)
 		}
 	} else {
@@ -81,6 +90,8 @@ func main() {
 					reorderedArgs = append(reorderedArgs, arg, os.Args[i+1])
 					skipNext = true
 				}
+			} else if arg == "-explain" || arg == "--explain" {
+				reorderedArgs = append(reorderedArgs, arg)
 			} else if !skipNext {
 				positionalArgs = append(positionalArgs, arg)
 			}
@@ -89,13 +100,15 @@ func main() {
 
 		// Define flags
 		outputFile = flag.String("o", "", "save output to file")
+		explain = flag.Bool("explain", false, "show how each line was parsed or why it was rejected")
 		flag.Parse()
 
 		// Get non-flag arguments
 		args = flag.Args()
 		if len(args) < 1 {
-			log.Error("usage: ingredients [file/url] [-o output.json]")
-			log.Error("       ingredients -stdin [-o output.json]")
+			log.Error("usage: ingredients [file/url] [-o output.json] [--explain]")
+			log.Error("       ingredients -stdin [-o output.json] [--explain]")
+			log.Error("       ingredients batch [-j workers] [-o output.ndjson] [urls.txt or -]")
 			os.Exit(1)
 		}
 	}
@@ -120,6 +133,9 @@ func main() {
 		}
 		
 		r, err = ingredients.NewFromHTML(origin, string(htmlBytes))
+		if *explain {
+			explainAndExit(r, err)
+		}
 		if err != nil {
 			log.Errorf("failed to parse HTML: %v", err)
 			os.Exit(1)
@@ -139,8 +155,8 @@ func main() {
 		io.WriteString(h, origin)
 		cachePath := filepath.Join(cacheDir, fmt.Sprintf("%x.json", h.Sum(nil)))
 
-		// Try to load from cache
-		if cachedData, err := os.ReadFile(cachePath); err == nil {
+		// Try to load from cache, unless explaining how the recipe is parsed
+		if cachedData, err := os.ReadFile(cachePath); err == nil && !*explain {
 			var cached Result
 			if json.Unmarshal(cachedData, &cached) == nil {
 				// Output cached result to stdout
@@ -157,14 +173,18 @@ func main() {
 			}
 		}
 
-		// Not in cache, fetch it
-		r, err = ingredients.NewFromFile(origin)
-		if err != nil {
+		// Not in cache, read the file or fetch the url
+		if _, statErr := os.Stat(origin); statErr == nil {
+			r, err = ingredients.NewFromFile(origin)
+		} else {
 			r, err = ingredients.NewFromURL(origin)
-			if err != nil {
-				log.Errorf("failed to fetch/parse URL: %v", err)
-				os.Exit(1)
-			}
+		}
+		if *explain {
+			explainAndExit(r, err)
+		}
+		if err != nil {
+			log.Errorf("failed to fetch/parse %s: %v", origin, err)
+			os.Exit(1)
 		}
 	}
 	ing := r.IngredientList()
@@ -202,3 +222,16 @@ func main() {
 		}
 	}
 }
+
+// explainAndExit prints how each line of the recipe was parsed, or why it
+// was rejected, and exits with an error if the recipe could not be parsed
+func explainAndExit(r *ingredients.Recipe, err error) {
+	if r != nil {
+		fmt.Print(r.Explain())
+	}
+	if err != nil {
+		log.Errorf("failed to parse: %v", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
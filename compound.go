@@ -0,0 +1,120 @@
+package ingredients
+
+import "strings"
+
+// compoundJoiners are the words between the parts of a compound measure
+var compoundJoiners = map[string]bool{
+	"plus": true,
+	"and":  true,
+}
+
+// PartsString renders a compound measure as it was written,
+// e.g. "1 cup plus 2 tablespoons" or "1 lb 4 oz"
+func (m Measure) PartsString() string {
+	var sb strings.Builder
+	for i, part := range m.Parts {
+		if i > 0 {
+			sb.WriteString(" ")
+			if part.Joiner != "" {
+				sb.WriteString(part.Joiner + " ")
+			}
+		}
+		sb.WriteString(AmountToString(part.Amount) + " " + part.Name)
+	}
+	return sb.String()
+}
+
+// getCompoundMeasure finds quantities made of several measures, like
+// "1 cup plus 2 tablespoons" or "1 lb 4 oz", and keeps each part.
+// Parts joined by "plus", "and" or "+" are always combined, but parts
+// that are only next to each other need to get smaller so that
+// "8 oz 2 cups" is not read as a single quantity.
+func (lineInfo *LineInfo) getCompoundMeasure(p *Parser) {
+	measure := &lineInfo.Ingredient.Measure
+	if len(lineInfo.MeasureInString) < 2 || measure.Amount == 0 || measure.IsRange() || measure.Package != nil {
+		return
+	}
+	runes := []rune(lineInfo.Line)
+	joined := strings.Contains(lineInfo.LineOriginal, "+")
+	parts := []Measure{{Amount: measure.Amount, Name: measure.Name}}
+	for i := 1; i < len(lineInfo.MeasureInString); i++ {
+		prev, next := lineInfo.MeasureInString[i-1], lineInfo.MeasureInString[i]
+		start := prev.Position + 1 + len([]rune(prev.Word))
+		end := next.Position + 1
+		if start > end || end > len(runes) {
+			break
+		}
+		fields := strings.Fields(string(runes[start:end]))
+		joiner := ""
+		if joined {
+			joiner = "+"
+		}
+		if len(fields) > 0 && compoundJoiners[fields[0]] {
+			joiner = fields[0]
+			fields = fields[1:]
+		}
+		amount, ok := sumNumbers(fields)
+		if !ok || (joiner == "" && !p.smallerUnit(lineInfo.Ingredient.Name, next.Word, parts[len(parts)-1].Name)) {
+			break
+		}
+		parts = append(parts, Measure{Amount: amount, Name: next.Word, Joiner: joiner})
+	}
+	if len(parts) > 1 {
+		measure.Parts = parts
+	}
+}
+
+// normalize converts the measure of the ingredient into cups. The parts
+// of a compound measure are added up and their total is expressed in the
+// unit of the first part, e.g. "1 lb 4 oz" becomes 1.25 lb.
+func (ing *Ingredient) normalize(p *Parser) (cups float64, err error) {
+	if len(ing.Measure.Parts) < 2 {
+		return p.normalizeIngredient(ing.Name, ing.Measure.Name, ing.Measure.Amount)
+	}
+	total := 0.0
+	for i, part := range ing.Measure.Parts {
+		ing.Measure.Parts[i].Cups, err = p.normalizeIngredient(ing.Name, part.Name, part.Amount)
+		if err != nil {
+			// fall back to the first part
+			ing.Measure.Parts = nil
+			return p.normalizeIngredient(ing.Name, ing.Measure.Name, ing.Measure.Amount)
+		}
+		total += ing.Measure.Parts[i].Cups
+	}
+	perUnit, err := p.normalizeIngredient(ing.Name, ing.Measure.Name, 1)
+	if err != nil || perUnit == 0 {
+		return
+	}
+	ing.Measure.Amount = total / perUnit
+	cups = total
+	return
+}
+
+// measureBetween reports whether a measure starts between two positions in the line
+func (lineInfo *LineInfo) measureBetween(start, end int) bool {
+	for _, wp := range lineInfo.MeasureInString {
+		if wp.Position >= start && wp.Position < end {
+			return true
+		}
+	}
+	return false
+}
+
+// sumNumbers adds up words like "2 ½" and reports whether every word was a number
+func sumNumbers(words []string) (total float64, ok bool) {
+	for _, word := range words {
+		v := ConvertStringToNumber(word)
+		if v <= 0 {
+			return 0, false
+		}
+		total += v
+	}
+	return total, len(words) > 0
+}
+
+// smallerUnit reports whether one unit holds less of the ingredient than another
+func (p *Parser) smallerUnit(ingredient, unit, than string) bool {
+	a, errA := p.normalizeIngredient(ingredient, unit, 1)
+	b, errB := p.normalizeIngredient(ingredient, than, 1)
+	return errA == nil && errB == nil && a < b
+}
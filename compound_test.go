@@ -0,0 +1,66 @@
+package ingredients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompoundMeasures(t *testing.T) {
+	tests := []struct {
+		input  string
+		amount float64
+		name   string
+		cups   float64
+		parts  int
+	}{
+		{"1 cup plus 2 tablespoons flour", 1.125, "cup", 1.125, 2},
+		{"1/2 cup + 2 Tablespoons Sugar", 0.625, "cup", 0.625, 2},
+		{"1 cup and 2 tablespoons milk", 1.125, "cup", 1.125, 2},
+		{"1 lb 4 oz chicken", 1.25, "lb", 0, 2},
+		{"2 cups flour", 2, "cups", 2, 0},
+		{"8 oz 2 cups cheddar cheese", 8, "oz", 0, 0},
+	}
+	for _, test := range tests {
+		r := &Recipe{FileName: "lines", FileContent: test.input}
+		_, lineInfo := scoreLine(test.input)
+		r.Lines = []LineInfo{lineInfo}
+		assert.Nil(t, r.parseRecipe(false))
+		if !assert.Equal(t, 1, len(r.Lines), test.input) {
+			continue
+		}
+		m := r.Lines[0].Ingredient.Measure
+		assert.InDelta(t, test.amount, m.Amount, 1e-9, test.input)
+		assert.Equal(t, test.name, m.Name, test.input)
+		if test.cups > 0 {
+			assert.InDelta(t, test.cups, m.Cups, 1e-9, test.input)
+		}
+		assert.Equal(t, test.parts, len(m.Parts), test.input)
+	}
+}
+
+func TestCompoundMeasuresMixedUnits(t *testing.T) {
+	r := &Recipe{FileName: "lines"}
+	_, lineInfo := scoreLine("60 grams plus 2 tablespoons peanut butter")
+	r.Lines = []LineInfo{lineInfo}
+	assert.Nil(t, r.parseRecipe(false))
+	assert.Equal(t, 1, len(r.Lines))
+	m := r.Lines[0].Ingredient.Measure
+	assert.Equal(t, 2, len(m.Parts))
+	assert.Equal(t, "grams", m.Name)
+	assert.InDelta(t, m.Parts[0].Cups+m.Parts[1].Cups, m.Cups, 1e-9)
+	assert.Greater(t, m.Amount, 60.0)
+}
+
+func TestCompoundMeasuresString(t *testing.T) {
+	ingredientList, err := ParseTextIngredients(`1 cup plus 2 tablespoons flour
+1 lb 4 oz chicken
+1/2 cup + 2 tablespoons sugar
+1 cup and 2 tablespoons milk`)
+	assert.Nil(t, err)
+	assert.Equal(t, `1 cup plus 2 tablespoons flour
+1 lb 4 oz chicken
+1/2 cup + 2 tablespoons sugar
+1 cup and 2 tablespoons milk`, strings.TrimSpace(ingredientList.String()))
+}
@@ -0,0 +1,184 @@
+package ingredients
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// conjunctions join the ingredients of a line. Ingredients joined by "and"
+// are each used, like "salt and pepper", while those joined by "or" are
+// alternatives, like "sugar or honey".
+var conjunctions = map[string]bool{
+	"and":    false,
+	"or":     true,
+	"and/or": true,
+}
+
+// conjunctionMaxWords is the most words that can follow a conjunction
+// before the next ingredient, as in "salt and freshly ground pepper"
+const conjunctionMaxWords = 2
+
+// conjoin returns the ingredient at index i of the matches in a line with
+// the ingredients joined to it by a conjunction, in the order of the line.
+// The first one is the main ingredient.
+func (p *Parser) conjoin(line string, matches []WordPosition, i int) []WordPosition {
+	runes := []rune(line)
+	first, last := i, i
+	for first > 0 && p.conjunctionBetween(runes, matches[first-1], matches[first]) != "" {
+		first--
+	}
+	for last < len(matches)-1 && p.conjunctionBetween(runes, matches[last], matches[last+1]) != "" {
+		last++
+	}
+	return slices.Clone(matches[first : last+1])
+}
+
+// conjunctionBetween returns the conjunction that joins two ingredients
+// of a line, or "" if they are not joined. Numbers and measures between
+// them mean they each have their own amount.
+func (p *Parser) conjunctionBetween(runes []rune, a, b WordPosition) string {
+	start := a.Position + utf8.RuneCountInString(a.Word) + 1
+	if start > b.Position+1 || b.Position+1 > len(runes) {
+		return ""
+	}
+	words := strings.Fields(string(runes[start : b.Position+1]))
+	if len(words) == 0 || len(words) > 1+conjunctionMaxWords {
+		return ""
+	}
+	if _, ok := conjunctions[words[0]]; !ok {
+		return ""
+	}
+	for _, word := range words[1:] {
+		_, isMeasure := p.corpus.Measures[word]
+		if isMeasure || ConvertStringToNumber(word) > 0 {
+			return ""
+		}
+	}
+	return words[0]
+}
+
+// split turns a line with ingredients joined by "and" into a line for
+// each of them, which share the measure of the line. Ingredients joined
+// by "or" are alternatives of each other, and the longest of them, like
+// "chocolate chip" over "milk", is the ingredient of its line.
+func (lineInfo *LineInfo) split(p *Parser) (lines []LineInfo) {
+	chain := lineInfo.IngredientsInString
+	if len(chain) < 2 {
+		return []LineInfo{*lineInfo}
+	}
+	runes := []rune(lineInfo.Line)
+	last := chain[len(chain)-1].Word
+	primary := lineInfo.Ingredient
+	ingredient := func(i int) Ingredient {
+		name := chain[i].Word
+		if i < len(chain)-1 {
+			name = p.elided(name, last)
+		}
+		if i == 0 && name == chain[0].Word {
+			return primary
+		}
+		return p.conjoinedIngredient(primary, name)
+	}
+	start := 0
+	for i := 1; i <= len(chain); i++ {
+		if i < len(chain) && conjunctions[p.conjunctionBetween(runes, chain[i-1], chain[i])] {
+			continue
+		}
+		main := start
+		for j := start + 1; j < i; j++ {
+			if len(chain[j].Word) > len(chain[main].Word) {
+				main = j
+			}
+		}
+		line := *lineInfo
+		line.IngredientsInString = []WordPosition{chain[main]}
+		line.Ingredient = ingredient(main)
+		for j := start; j < i; j++ {
+			if j != main {
+				line.Ingredient.Alternatives = append(line.Ingredient.Alternatives, ingredient(j))
+			}
+		}
+		lines = append(lines, line)
+		start = i
+	}
+	return
+}
+
+// splitAmounts splits a line with ingredients that each have their own
+// amount, like "1 cup sugar and 2 tablespoons honey", into a line for
+// each of them. Notes in parentheses are not split.
+func (p *Parser) splitAmounts(lineInfo LineInfo) []LineInfo {
+	lower := strings.ToLower(lineInfo.LineOriginal)
+	for offset := 0; ; {
+		i := strings.Index(lower[offset:], " and ")
+		if i < 0 {
+			return []LineInfo{lineInfo}
+		}
+		i += offset
+		offset = i + 1
+		if strings.Count(lower[:i], "(") > strings.Count(lower[:i], ")") {
+			continue
+		}
+		_, before := p.scoreLine(lineInfo.LineOriginal[:i])
+		_, after := p.scoreLine(lineInfo.LineOriginal[i+len(" and "):])
+		if len(before.IngredientsInString) == 0 || len(before.AmountInString) == 0 ||
+			len(after.IngredientsInString) == 0 || len(after.AmountInString) == 0 || after.AmountInString[0].Position != 0 {
+			continue
+		}
+		for _, part := range []*LineInfo{&before, &after} {
+			part.Source = lineInfo.Source
+			part.Ingredient.Group = lineInfo.Ingredient.Group
+		}
+		return append([]LineInfo{before}, p.splitAmounts(after)...)
+	}
+}
+
+// elided returns the full name of an ingredient that is only the first
+// words of the last ingredient of the line, like the "milk" of "milk or
+// milk chocolate chips", which is "milk chocolate". Names that are not
+// the start of the last one are kept as they are, so "butter or
+// margarine" is not joined into "butter margarine".
+func (p *Parser) elided(word, last string) string {
+	words := strings.Fields(last)
+	size := len(strings.Fields(word))
+	if !strings.HasPrefix(last, word+" ") {
+		return word
+	}
+	for length := len(words) - 1; length > size; length-- {
+		name := strings.Join(words[:length], " ")
+		if matches := p.ingredientsTrie.findAll(name); len(matches) == 1 && matches[0].Word == name {
+			return name
+		}
+	}
+	return word
+}
+
+// conjoinedIngredient returns an ingredient that shares the measure and
+// description of another
+func (p *Parser) conjoinedIngredient(primary Ingredient, word string) (ing Ingredient) {
+	ing = primary
+	ing.Name = p.singular(word)
+	ing.ID = p.canonical(ing.Name)
+	ing.Category = p.category(ing.Name)
+	ing.Preparation = slices.Clone(primary.Preparation)
+	ing.Alternatives = nil
+	ing.Measure.Parts = slices.Clone(primary.Measure.Parts)
+	var err error
+	ing.Measure.Cups, err = ing.normalize(p)
+	if err != nil {
+		p.logger.Tracef("[%s]: %s", ing.Name, err.Error())
+	}
+	if err = ing.weigh(p); err != nil {
+		p.logger.Tracef("[%s]: %s", ing.Name, err.Error())
+	}
+	return
+}
+
+// changeAlternatives applies a change of measure to the alternatives of
+// an ingredient
+func (ing *Ingredient) changeAlternatives(change func(Measure) Measure) {
+	for i := range ing.Alternatives {
+		ing.Alternatives[i].Measure = change(ing.Alternatives[i].Measure)
+	}
+}
@@ -0,0 +1,117 @@
+package ingredients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConjunctions(t *testing.T) {
+	r := parseLines(t, `1 teaspoon salt and pepper
+1 cup sugar or honey
+1 cup sugar and 2 tablespoons honey
+1 cup milk or semisweet chocolate chips
+2 tablespoons butter or margarine
+salt and pepper to taste`)
+	assert.Equal(t, 9, len(r.Lines))
+
+	// "and" gives an ingredient for each, sharing the measure
+	assert.Equal(t, "salt", r.Lines[0].Ingredient.Name)
+	assert.Equal(t, "pepper", r.Lines[1].Ingredient.Name)
+	assert.Equal(t, r.Lines[0].Ingredient.Measure.Amount, r.Lines[1].Ingredient.Measure.Amount)
+	assert.Equal(t, "teaspoon", r.Lines[1].Ingredient.Measure.Name)
+	assert.Equal(t, r.Lines[0].LineOriginal, r.Lines[1].LineOriginal)
+
+	// "or" gives alternatives, weighed on their own
+	sugar := r.Lines[2].Ingredient
+	assert.Equal(t, "sugar", sugar.Name)
+	assert.Equal(t, 1, len(sugar.Alternatives))
+	assert.Equal(t, "honey", sugar.Alternatives[0].Name)
+	assert.Equal(t, 1.0, sugar.Alternatives[0].Measure.Cups)
+	assert.NotEqual(t, sugar.Measure.Weight, sugar.Alternatives[0].Measure.Weight)
+
+	// ingredients with their own amounts are not joined, but split
+	assert.Equal(t, "sugar", r.Lines[3].Ingredient.Name)
+	assert.Nil(t, r.Lines[3].Ingredient.Alternatives)
+	assert.Equal(t, "1 cup sugar", r.Lines[3].LineOriginal)
+	assert.Equal(t, "honey", r.Lines[4].Ingredient.Name)
+	assert.Equal(t, 2.0, r.Lines[4].Ingredient.Measure.Amount)
+	assert.Equal(t, "tablespoons", r.Lines[4].Ingredient.Measure.Name)
+	assert.Equal(t, "2 tablespoons honey", r.Lines[4].LineOriginal)
+
+	// the longest of the alternatives is the ingredient of the line
+	assert.Equal(t, "chocolate chip", r.Lines[5].Ingredient.Name)
+	assert.Equal(t, "milk", r.Lines[5].Ingredient.Alternatives[0].Name)
+
+	// ingredients that are whole on their own are not joined
+	assert.Equal(t, "margarine", r.Lines[6].Ingredient.Name)
+	assert.Equal(t, "butter", r.Lines[6].Ingredient.Alternatives[0].Name)
+
+	// lines with a conjunction are kept without an amount
+	assert.Equal(t, "salt", r.Lines[7].Ingredient.Name)
+	assert.Equal(t, "pepper", r.Lines[8].Ingredient.Name)
+	assert.True(t, r.Lines[7].Ingredient.ToTaste)
+	assert.True(t, r.Lines[8].Ingredient.ToTaste)
+
+	assert.Equal(t, `1 teaspoon salt
+1 teaspoon pepper
+1 cup sugar or honey
+1 cup sugar
+2 tablespoons honey
+1 cup chocolate chip or milk
+2 tablespoons margarine or butter
+0 whole salt
+0 whole pepper
+`, r.IngredientList().String())
+	// sugar is not merged with the sugar that has an alternative, while
+	// the salt and pepper to taste are merged with the teaspoon of each
+	assert.Equal(t, 7, len(r.Ingredients))
+	assert.Equal(t, "sugar", r.Ingredients[2].Name)
+	assert.Equal(t, 1.0, r.Ingredients[2].Measure.Amount)
+	assert.Equal(t, "honey", r.Ingredients[2].Alternatives[0].Name)
+	assert.Equal(t, "sugar", r.Ingredients[3].Name)
+	assert.Nil(t, r.Ingredients[3].Alternatives)
+
+	// alternatives are scaled with their ingredient
+	_, err := r.Scale(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, r.Lines[2].Ingredient.Alternatives[0].Measure.Amount)
+	assert.Equal(t, 2.0, r.Ingredients[2].Alternatives[0].Measure.Amount)
+}
+
+func TestConjunctionsConsolidate(t *testing.T) {
+	r := parseLines(t, `1 cup sugar or honey
+1/2 cup flour
+1 cup sugar or honey`)
+	assert.Equal(t, 2, len(r.Ingredients))
+	assert.Equal(t, 2.0, r.Ingredients[0].Measure.Amount)
+	assert.Equal(t, 2.0, r.Ingredients[0].Alternatives[0].Measure.Amount)
+	assert.Equal(t, 2.0, r.Ingredients[0].Alternatives[0].Measure.Cups)
+	// the lines keep their own alternatives
+	assert.Equal(t, 1.0, r.Lines[0].Ingredient.Alternatives[0].Measure.Amount)
+}
+
+func TestSplitAmounts(t *testing.T) {
+	tests := map[string]int{
+		"1 cup sugar and 2 tablespoons honey":                      2,
+		"1 cup flour, 1 egg and 2 cups milk and 1 pinch salt":      3,
+		"1 teaspoon salt and pepper":                               1,
+		"1 1/2 cups and 2 tablespoons flour":                       1,
+		"2 cups flour (or 1 cup cake flour and 1 cup all-purpose)": 1,
+	}
+	for line, parts := range tests {
+		_, lineInfo := scoreLine(line)
+		assert.Equal(t, parts, len(defaultParser.splitAmounts(lineInfo)), line)
+	}
+}
+
+func TestElided(t *testing.T) {
+	tests := map[[2]string]string{
+		{"milk", "milk chocolate chips"}: "milk chocolate",
+		{"milk", "chocolate chips"}:      "milk",
+		{"butter", "margarine"}:          "butter",
+	}
+	for words, name := range tests {
+		assert.Equal(t, name, defaultParser.elided(words[0], words[1]), words)
+	}
+}
@@ -0,0 +1,187 @@
+package ingredients
+
+import (
+	"fmt"
+	"math"
+)
+
+// UnitSystem is the set of units that ConvertTo rewrites measures into.
+// Weight can be combined with another system to pick the weight units,
+// e.g. Weight|USCustomary weighs everything in ounces and pounds.
+type UnitSystem int
+
+const (
+	// USCustomary uses teaspoons, tablespoons and cups, and ounces and pounds
+	USCustomary UnitSystem = 1 << iota
+	// Metric uses milliliters and liters, and grams and kilograms
+	Metric
+	// Imperial uses teaspoons, tablespoons, imperial fluid ounces and pints,
+	// and ounces and pounds. Dry goods are weighed in grams, as in British
+	// recipes, unless the amount is a spoonful.
+	Imperial
+	// Weight measures every ingredient that can be weighed by weight
+	Weight
+)
+
+const (
+	millilitersPerCup         = 236.588
+	millilitersPerImperialOz  = 28.4131
+	millilitersPerImperialPt  = 568.261
+	millilitersPerTablespoon  = 14.7868
+	millilitersPerTeaspoon    = 4.92892
+	gramsPerOunce             = 28.3495
+	gramsPerPound             = 453.592
+	ouncesBeforePounds        = 16
+	gramsBeforeKilograms      = 1000
+	millilitersBeforeLiters   = 1000
+	imperialOuncesBeforePints = 20
+)
+
+// ConvertTo rewrites the measure of every ingredient into the units of
+// a system, choosing the unit by the size of the amount. Whole items are
+// kept unless converting by Weight, and measures that could not be
+// normalized are left alone.
+func (r *Recipe) ConvertTo(system UnitSystem) (err error) {
+	volumeSystems := 0
+	for _, s := range []UnitSystem{USCustomary, Metric, Imperial} {
+		if system&s != 0 {
+			volumeSystems++
+		}
+	}
+	if volumeSystems > 1 || (volumeSystems == 0 && system != Weight) {
+		err = fmt.Errorf("%w %d", ErrUnknownUnitSystem, system)
+		return
+	}
+
+	p := r.getParser()
+	convert := func(ing *Ingredient) {
+		ing.Measure = p.convertMeasure(ing.Measure, ing.Category, system)
+		for i := range ing.Alternatives {
+			alternative := &ing.Alternatives[i]
+			alternative.Measure = p.convertMeasure(alternative.Measure, alternative.Category, system)
+		}
+	}
+	for i := range r.Lines {
+		convert(&r.Lines[i].Ingredient)
+	}
+	for i := range r.Ingredients {
+		convert(&r.Ingredients[i])
+	}
+	for i := range r.Groups {
+		for j := range r.Groups[i].Ingredients {
+			convert(&r.Groups[i].Ingredients[j])
+		}
+	}
+	return
+}
+
+// weighedCategories are the categories of the dry goods that Imperial
+// weighs
+var weighedCategories = map[Category]bool{
+	CategoryHerbSpice: true,
+	CategoryFruit:     true,
+	CategoryVegetable: true,
+	CategoryProtein:   true,
+	CategoryGrain:     true,
+	CategorySweetener: true,
+	CategoryOther:     true,
+}
+
+// convertMeasure converts one measure of an ingredient of a category into
+// a unit system. Without a category, volumes stay volumes.
+func (p *Parser) convertMeasure(m Measure, category Category, system UnitSystem) Measure {
+	isWeight := false
+	if normalized, ok := p.corpus.Measures[m.Name]; ok {
+		_, isWeight = gramConversions[normalized]
+	}
+	isDry := system&Imperial != 0 && weighedCategories[category] && m.WeightSource == WeightDensity &&
+		m.Cups*millilitersPerCup >= 2*millilitersPerImperialOz
+
+	var amount float64
+	var name string
+	switch {
+	case m.Weight > 0 && (system&Weight != 0 || isWeight):
+		amount, name = p.weightUnits(m.Weight, system)
+	case m.Weight > 0 && isDry:
+		amount, name = p.weightUnits(m.Weight, Metric)
+	case m.Name == "whole" || m.Cups == 0:
+		return m
+	default:
+		amount, name = p.volumeUnits(m.Cups, system)
+	}
+
+	converted := Measure{
+		Amount:       amount,
+		Name:         name,
+		Cups:         m.Cups,
+		Weight:       m.Weight,
+		WeightSource: m.WeightSource,
+		Approximate:  m.Approximate,
+	}
+	if m.IsRange() && m.Amount > 0 {
+		ratio := amount / m.Amount
+		converted.Min, converted.Max = m.Min*ratio, m.Max*ratio
+	}
+	return converted
+}
+
+// weightUnits chooses grams or kilograms for metric weights and
+// ounces or pounds otherwise
+func (p *Parser) weightUnits(grams float64, system UnitSystem) (amount float64, name string) {
+	if system&(USCustomary|Imperial) != 0 {
+		ounces := grams / gramsPerOunce
+		if ounces >= ouncesBeforePounds {
+			return grams / gramsPerPound, p.pluralUnit("pound", grams/gramsPerPound)
+		}
+		return ounces, p.pluralUnit("ounce", ounces)
+	}
+	if grams >= gramsBeforeKilograms {
+		return roundTo(grams/1000, 0.01), "kg"
+	}
+	return roundTo(grams, 1), "g"
+}
+
+// volumeUnits chooses the spoon, cup or liquid unit for a volume in cups
+func (p *Parser) volumeUnits(cups float64, system UnitSystem) (amount float64, name string) {
+	switch {
+	case system&Metric != 0:
+		ml := cups * millilitersPerCup
+		if ml >= millilitersBeforeLiters {
+			return roundTo(ml/1000, 0.01), "l"
+		}
+		if ml >= 50 {
+			return roundTo(ml, 5), "ml"
+		}
+		return roundTo(ml, 1), "ml"
+	case system&Imperial != 0:
+		ml := cups * millilitersPerCup
+		if cups < 0.0625 {
+			amount = roundTo(ml/millilitersPerTeaspoon, 0.125)
+			return amount, p.pluralUnit("teaspoon", amount)
+		} else if ml < 2*millilitersPerImperialOz {
+			amount = roundTo(ml/millilitersPerTablespoon, 0.125)
+			return amount, p.pluralUnit("tablespoon", amount)
+		} else if ml < imperialOuncesBeforePints*millilitersPerImperialOz {
+			amount = roundTo(ml/millilitersPerImperialOz, 0.25)
+			return amount, p.pluralUnit("imperial fluid ounce", amount)
+		}
+		amount = roundTo(ml/millilitersPerImperialPt, 0.125)
+		return amount, p.pluralUnit("imperial pint", amount)
+	default:
+		amount, name, _, _ = determineMeasurementsFromCups(cups)
+		return amount, p.pluralUnit(name, amount)
+	}
+}
+
+// pluralUnit pluralizes a unit for amounts above one
+func (p *Parser) pluralUnit(name string, amount float64) string {
+	if amount > 1 {
+		return p.plural(name)
+	}
+	return name
+}
+
+// roundTo rounds to the nearest multiple of a step
+func roundTo(amount, step float64) float64 {
+	return math.Round(amount/step) * step
+}
@@ -0,0 +1,110 @@
+package ingredients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertTo(t *testing.T) {
+	lines := `2 cups flour
+1 tablespoon vanilla
+1 lb chicken
+2 whole eggs
+4 cups milk`
+	tests := []struct {
+		system   UnitSystem
+		expected string
+	}{
+		{USCustomary, `2 cups flour
+1 tablespoon vanilla
+1 pound chicken
+2 whole eggs
+4 cups milk`},
+		{Metric, `475 ml flour
+15 ml vanilla
+454 g chicken
+2 whole eggs
+945 ml milk`},
+		{Imperial, `227 g flour
+1 tablespoon vanilla
+1 pound chicken
+2 whole eggs
+1 5/8 imperial pints milk`},
+		{Weight, `227 g flour
+11 g vanilla
+454 g chicken
+100 g egg
+881 g milk`},
+		{Weight | USCustomary, `8 ounces flour
+3/8 ounce vanilla
+1 pound chicken
+3 1/2 ounces egg
+2 pounds milk`},
+	}
+	for _, test := range tests {
+		r := &Recipe{FileName: "lines"}
+		_, r.Lines = scoreLines(strings.Split(lines, "\n"))
+		assert.Nil(t, r.parseRecipe(false))
+		assert.Nil(t, r.ConvertTo(test.system))
+		assert.Equal(t, test.expected, strings.TrimSpace(r.IngredientList().String()), test.system)
+	}
+}
+
+func TestConvertToRoundTrip(t *testing.T) {
+	lines := []string{"2 cups flour", "1 tablespoon vanilla", "4 cups milk", "1/2 cup butter"}
+	for _, system := range []UnitSystem{USCustomary, Metric, Imperial} {
+		r := &Recipe{FileName: "lines"}
+		_, r.Lines = scoreLines(lines)
+		assert.Nil(t, r.parseRecipe(false))
+		assert.Nil(t, r.ConvertTo(system))
+
+		converted, err := ParseTextIngredients(r.IngredientList().String())
+		assert.Nil(t, err, system)
+		if !assert.Equal(t, len(r.Ingredients), len(converted.Ingredients), system) {
+			continue
+		}
+		for i, ing := range converted.Ingredients {
+			want := r.Ingredients[i].Measure.Cups
+			assert.Equal(t, r.Ingredients[i].Name, ing.Name, system)
+			// amounts are rounded to fractions when printed
+			assert.InEpsilon(t, want, ing.Measure.Cups, 0.03, "%d: %s %s", system, ing.Measure.Name, ing.Name)
+		}
+	}
+}
+
+func TestConvertToImperial(t *testing.T) {
+	r := &Recipe{FileName: "lines"}
+	_, r.Lines = scoreLines([]string{"3 1/2 cups flour", "1 cup sugar", "1 tablespoon flour", "1 cup water", "1/3 cup milk"})
+	assert.Nil(t, r.parseRecipe(false))
+	assert.Nil(t, r.ConvertTo(Imperial))
+	// dry goods are weighed unless they are a spoonful, and every amount
+	// is rounded
+	assert.Equal(t, `398 g flour
+206 g sugar
+1 tablespoon flour
+8 1/4 imperial fluid ounces water
+2 3/4 imperial fluid ounces milk`, strings.TrimSpace(r.IngredientList().String()))
+}
+
+func TestConvertToKeepsTotals(t *testing.T) {
+	r := &Recipe{FileName: "lines"}
+	_, r.Lines = scoreLines([]string{"1 to 2 cups sugar", "1 teaspoon salt"})
+	assert.Nil(t, r.parseRecipe(false))
+	cups := r.Ingredients[0].Measure.Cups
+	assert.Nil(t, r.ConvertTo(Metric))
+	m := r.Ingredients[0].Measure
+	assert.Equal(t, "ml", m.Name)
+	assert.Equal(t, cups, m.Cups)
+	assert.True(t, m.IsRange())
+	assert.InDelta(t, m.Max/2, m.Min, 1)
+	assert.Equal(t, "5 ml", AmountToString(r.Ingredients[1].Measure.Amount)+" "+r.Ingredients[1].Measure.Name)
+}
+
+func TestConvertToInvalid(t *testing.T) {
+	r := &Recipe{}
+	assert.NotNil(t, r.ConvertTo(Metric|Imperial))
+	assert.NotNil(t, r.ConvertTo(0))
+	assert.Nil(t, r.ConvertTo(Weight|Metric))
+}
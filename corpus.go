@@ -412,6 +412,246 @@ var proteinMap = map[string]struct{}{
 	"venison":    {},
 }
 
+var spiceMap = map[string]struct{}{
+	"allspice":          {},
+	"black pepper":      {},
+	"caraway seed":      {},
+	"cardamom":          {},
+	"cayenne":           {},
+	"cayenne pepper":    {},
+	"celery seed":       {},
+	"chili powder":      {},
+	"cinnamon":          {},
+	"clove":             {},
+	"coriander":         {},
+	"cumin":             {},
+	"curry powder":      {},
+	"fennel seed":       {},
+	"fenugreek":         {},
+	"garam masala":      {},
+	"garlic powder":     {},
+	"ginger":            {},
+	"italian seasoning": {},
+	"mace":              {},
+	"mustard seed":      {},
+	"nutmeg":            {},
+	"onion powder":      {},
+	"paprika":           {},
+	"pepper":            {},
+	"peppercorn":        {},
+	"poppy seed":        {},
+	"pumpkin pie spice": {},
+	"saffron":           {},
+	"salt":              {},
+	"smoked paprika":    {},
+	"star anise":        {},
+	"sumac":             {},
+	"turmeric":          {},
+	"vanilla":           {},
+	"vanilla bean":      {},
+	"white pepper":      {},
+}
+
+var dairyMap = map[string]struct{}{
+	"aged cheddar":    {},
+	"blue cheese":     {},
+	"buttermilk":      {},
+	"cheddar":         {},
+	"cheddar cheese":  {},
+	"cheese":          {},
+	"condensed milk":  {},
+	"cottage cheese":  {},
+	"cream":           {},
+	"cream cheese":    {},
+	"creme fraiche":   {},
+	"evaporated milk": {},
+	"feta":            {},
+	"goat cheese":     {},
+	"greek yogurt":    {},
+	"gruyere":         {},
+	"half and half":   {},
+	"heavy cream":     {},
+	"mascarpone":      {},
+	"milk":            {},
+	"mozzarella":      {},
+	"parmesan":        {},
+	"parmesan cheese": {},
+	"ricotta":         {},
+	"skim milk":       {},
+	"sour cream":      {},
+	"swiss cheese":    {},
+	"whipping cream":  {},
+	"yogurt":          {},
+}
+
+var grainMap = map[string]struct{}{
+	"almond flour":      {},
+	"barley":            {},
+	"basmati rice":      {},
+	"bran":              {},
+	"bread":             {},
+	"bread flour":       {},
+	"breadcrumb":        {},
+	"brown rice":        {},
+	"buckwheat":         {},
+	"bulgur":            {},
+	"cake flour":        {},
+	"cornmeal":          {},
+	"cornstarch":        {},
+	"couscous":          {},
+	"cracker":           {},
+	"farro":             {},
+	"flour":             {},
+	"jasmine rice":      {},
+	"millet":            {},
+	"noodle":            {},
+	"oat":               {},
+	"oatmeal":           {},
+	"panko":             {},
+	"pasta":             {},
+	"polenta":           {},
+	"quinoa":            {},
+	"rice":              {},
+	"rye":               {},
+	"semolina":          {},
+	"spaghetti":         {},
+	"tortilla":          {},
+	"wheat germ":        {},
+	"white rice":        {},
+	"whole wheat flour": {},
+}
+
+var sweetenerMap = map[string]struct{}{
+	"agave":               {},
+	"agave nectar":        {},
+	"brown sugar":         {},
+	"caster sugar":        {},
+	"chocolate":           {},
+	"chocolate chip":      {},
+	"cocoa powder":        {},
+	"coconut sugar":       {},
+	"confectioners sugar": {},
+	"corn syrup":          {},
+	"date syrup":          {},
+	"golden syrup":        {},
+	"honey":               {},
+	"icing sugar":         {},
+	"jam":                 {},
+	"maple syrup":         {},
+	"molasses":            {},
+	"powdered sugar":      {},
+	"stevia":              {},
+	"sugar":               {},
+	"superfine sugar":     {},
+	"turbinado sugar":     {},
+}
+
+var fatMap = map[string]struct{}{
+	"avocado oil":          {},
+	"butter":               {},
+	"canola oil":           {},
+	"coconut oil":          {},
+	"cooking spray":        {},
+	"ghee":                 {},
+	"lard":                 {},
+	"margarine":            {},
+	"mayonnaise":           {},
+	"oil":                  {},
+	"olive oil":            {},
+	"peanut butter":        {},
+	"peanut oil":           {},
+	"sesame oil":           {},
+	"shortening":           {},
+	"sunflower oil":        {},
+	"tahini":               {},
+	"vegetable oil":        {},
+	"vegetable shortening": {},
+}
+
+var liquidMap = map[string]struct{}{
+	"almond milk":          {},
+	"apple cider vinegar":  {},
+	"balsamic vinegar":     {},
+	"beef broth":           {},
+	"beef stock":           {},
+	"beer":                 {},
+	"bourbon":              {},
+	"brandy":               {},
+	"broth":                {},
+	"chicken broth":        {},
+	"chicken stock":        {},
+	"coconut milk":         {},
+	"coffee":               {},
+	"espresso":             {},
+	"fish sauce":           {},
+	"juice":                {},
+	"lemon juice":          {},
+	"lime juice":           {},
+	"orange juice":         {},
+	"red wine":             {},
+	"rice vinegar":         {},
+	"rum":                  {},
+	"soy sauce":            {},
+	"vegetable broth":      {},
+	"vegetable stock":      {},
+	"vinegar":              {},
+	"vodka":                {},
+	"water":                {},
+	"white wine":           {},
+	"wine":                 {},
+	"worcestershire sauce": {},
+}
+
+var preparationMap = map[string]struct{}{
+	"beaten":           {},
+	"blanched":         {},
+	"boiled":           {},
+	"chilled":          {},
+	"chopped":          {},
+	"cooked":           {},
+	"cooled":           {},
+	"cored":            {},
+	"crumbled":         {},
+	"crushed":          {},
+	"cubed":            {},
+	"deseeded":         {},
+	"deveined":         {},
+	"diced":            {},
+	"drained":          {},
+	"grated":           {},
+	"halved":           {},
+	"hulled":           {},
+	"juiced":           {},
+	"julienned":        {},
+	"mashed":           {},
+	"melted":           {},
+	"minced":           {},
+	"packed":           {},
+	"peeled":           {},
+	"pitted":           {},
+	"quartered":        {},
+	"rinsed":           {},
+	"room temperature": {},
+	"scrubbed":         {},
+	"seeded":           {},
+	"shelled":          {},
+	"shredded":         {},
+	"sifted":           {},
+	"sliced":           {},
+	"slivered":         {},
+	"snipped":          {},
+	"softened":         {},
+	"squeezed":         {},
+	"stemmed":          {},
+	"thawed":           {},
+	"toasted":          {},
+	"torn":             {},
+	"trimmed":          {},
+	"warmed":           {},
+	"whisked":          {},
+	"zested":           {},
+}
+
 var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" nonhydrogenated margarine ",
 	" pomegranate concentrates ",
@@ -424,7 +664,6 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" miniature marshmallows ",
 	" raspberry blackberries ",
 	" raspberry vinaigrettes ",
-	" salt and black peppers ",
 	" balsamic vinaigrettes ",
 	" butterscotch puddings ",
 	" chanterelle mushrooms ",
@@ -441,7 +680,6 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" monosodium glutamates ",
 	" new zealand spinaches ",
 	" raspberry vinaigrette ",
-	" salt and black pepper ",
 	" sweetened cranberries ",
 	" tamarind concentrates ",
 	" vegetable shortenings ",
@@ -452,6 +690,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" apple cider vinegars ",
 	" balsamic vinaigrette ",
 	" benedictine liqueurs ",
+	" bicarbonate of sodas ",
 	" blueberry blackberry ",
 	" butterscotch pudding ",
 	" chanterelle mushroom ",
@@ -491,6 +730,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" assorted vegetables ",
 	" balsamic reductions ",
 	" benedictine liqueur ",
+	" bicarbonate of soda ",
 	" blackberry brandies ",
 	" bolivian corianders ",
 	" bucatini spaghettis ",
@@ -900,6 +1140,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" smoked bluefishes ",
 	" smoked bratwursts ",
 	" smoked mozzarella ",
+	" soda bicarbonates ",
 	" solid shortenings ",
 	" sourdough crouton ",
 	" sourdough starter ",
@@ -1225,7 +1466,6 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" roquefort cheese ",
 	" ruby grapefruits ",
 	" saffron optional ",
-	" salt and peppers ",
 	" saltine crackers ",
 	" sauvignon blancs ",
 	" seltzer chilleds ",
@@ -1240,6 +1480,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" smoked kielbasas ",
 	" smoked mackerels ",
 	" smoked whitefish ",
+	" soda bicarbonate ",
 	" solid shortening ",
 	" sourdough breads ",
 	" southern comfort ",
@@ -1757,7 +1998,6 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" saffron strands ",
 	" saffron threads ",
 	" salad dressings ",
-	" salt and pepper ",
 	" saltine cracker ",
 	" sandwich breads ",
 	" saskatoon berry ",
@@ -2689,6 +2929,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" cashew halves ",
 	" cassava flour ",
 	" cassi liqueur ",
+	" caster sugars ",
 	" celery hearts ",
 	" celery powder ",
 	" chaat masalas ",
@@ -3383,6 +3624,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" carrot juice ",
 	" cashew cream ",
 	" cashew milks ",
+	" caster sugar ",
 	" cauliflowers ",
 	" celery heart ",
 	" celery seeds ",
@@ -6177,6 +6419,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" barolos ",
 	" berbere ",
 	" berries ",
+	" bicarbs ",
 	" biscuit ",
 	" bisques ",
 	" bologna ",
@@ -6574,6 +6817,7 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" basils ",
 	" basses ",
 	" besans ",
+	" bicarb ",
 	" bisons ",
 	" bisque ",
 	" boldos ",
@@ -7360,7 +7604,6 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" jam ",
 	" lox ",
 	" m m ",
-	" mls ",
 	" msg ",
 	" nut ",
 	" oat ",
@@ -7376,9 +7619,19 @@ var corpusIngredients = []string{" nonhydrogenated margarines ",
 	" rye ",
 	" tip ",
 	" yam ",
-	" ml ",
 	" ro "}
-var corpusMeasures = []string{" tablespoon.. ",
+
+var corpusMeasures = []string{" imperial fluid ounces. ",
+	" imperial fluid ounce. ",
+	" imperial fluid ounces ",
+	" imperial fluid ounce ",
+	" imperial fl oz. ",
+	" imperial pints. ",
+	" imperial fl oz ",
+	" imperial pint. ",
+	" imperial pints ",
+	" imperial pint ",
+	" tablespoon.. ",
 	" tablespoons. ",
 	" milliliter. ",
 	" tablespoon. ",
@@ -7386,9 +7639,11 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" milliliter ",
 	" tablespoon ",
 	" teaspoons. ",
+	" kilograms ",
 	" teaspoon. ",
 	" teaspoons ",
 	" grams... ",
+	" kilogram ",
 	" ounces.. ",
 	" tblsp... ",
 	" teaspoon ",
@@ -7403,6 +7658,8 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" canned ",
 	" cans.. ",
 	" grams. ",
+	" liters ",
+	" litres ",
 	" ounce. ",
 	" ounces ",
 	" pint.. ",
@@ -7421,6 +7678,8 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" cups. ",
 	" gram. ",
 	" grams ",
+	" liter ",
+	" litre ",
 	" ounce ",
 	" pint. ",
 	" pints ",
@@ -7436,6 +7695,7 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" cup. ",
 	" cups ",
 	" gram ",
+	" lbs. ",
 	" oz.. ",
 	" pint ",
 	" tbl. ",
@@ -7447,6 +7707,9 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" c.. ",
 	" can ",
 	" cup ",
+	" kg. ",
+	" lb. ",
+	" lbs ",
 	" ml. ",
 	" oz. ",
 	" tbl ",
@@ -7454,11 +7717,15 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" tsp ",
 	" c. ",
 	" g. ",
+	" kg ",
+	" l. ",
+	" lb ",
 	" ml ",
 	" oz ",
 	" t. ",
 	" c ",
 	" g ",
+	" l ",
 	" t "}
 var corpusNumbers = []string{" 1/2 ",
 	" 1/3 ",
@@ -7496,6 +7763,7 @@ var corpusNumbers = []string{" 1/2 ",
 	" 19 ",
 	" 20 ",
 	" ⅛ ",
+	" ⅙ ",
 	" ⅝ ",
 	" ⅔ ",
 	" ⅓ ",
@@ -8346,6 +8614,7 @@ var corpusFractionNumberMap = map[string]fractionNumber{
 	"¾": fractionNumber{"3/4", 0.7500000000},
 	"⅓": fractionNumber{"1/3", 0.3333333333},
 	"⅔": fractionNumber{"2/3", 0.6666666667},
+	"⅙": fractionNumber{"1/6", 0.1666666667},
 	"⅛": fractionNumber{"1/8", 0.1250000000},
 	"⅜": fractionNumber{"3/8", 0.3750000000},
 	"⅝": fractionNumber{"5/8", 0.6250000000},
@@ -8353,88 +8622,112 @@ var corpusFractionNumberMap = map[string]fractionNumber{
 }
 
 var corpusMeasuresMap = map[string]string{
-	"c":            "cup",
-	"c.":           "cup",
-	"c..":          "cup",
-	"can":          "can",
-	"can.":         "can",
-	"canned":       "can",
-	"canned.":      "can",
-	"cans":         "can",
-	"cans.":        "can",
-	"cans..":       "can",
-	"cup":          "cup",
-	"cup.":         "cup",
-	"cup..":        "cup",
-	"cups":         "cup",
-	"cups.":        "cup",
-	"g":            "gram",
-	"g.":           "gram",
-	"gram":         "gram",
-	"gram.":        "gram",
-	"grams":        "gram",
-	"grams.":       "gram",
-	"grams..":      "gram",
-	"grams...":     "gram",
-	"milliliter":   "milliliter",
-	"milliliter.":  "milliliter",
-	"ml":           "milliliter",
-	"ml.":          "milliliter",
-	"ounce":        "ounce",
-	"ounce.":       "ounce",
-	"ounces":       "ounce",
-	"ounces.":      "ounce",
-	"ounces..":     "ounce",
-	"oz":           "ounce",
-	"oz.":          "ounce",
-	"oz..":         "ounce",
-	"pint":         "pint",
-	"pint.":        "pint",
-	"pint..":       "pint",
-	"pints":        "pint",
-	"pints.":       "pint",
-	"pints..":      "pint",
-	"pound":        "pound",
-	"pound.":       "pound",
-	"pounds":       "pound",
-	"pounds.":      "pound",
-	"quart":        "quart",
-	"quart.":       "quart",
-	"quart..":      "quart",
-	"quarts":       "quart",
-	"quarts.":      "quart",
-	"t":            "tsp",
-	"t.":           "tsp",
-	"tablespoon":   "tbl",
-	"tablespoon.":  "tbl",
-	"tablespoon..": "tbl",
-	"tablespoons":  "tbl",
-	"tablespoons.": "tbl",
-	"tbl":          "tbl",
-	"tbl.":         "tbl",
-	"tbls":         "tbl",
-	"tbls.":        "tbl",
-	"tbls..":       "tbl",
-	"tblsp":        "tbl",
-	"tblsp.":       "tbl",
-	"tblsp..":      "tbl",
-	"tblsp...":     "tbl",
-	"tbs":          "tbl",
-	"tbs.":         "tbl",
-	"tbsp":         "tbl",
-	"tbsp.":        "tbl",
-	"tbsp..":       "tbl",
-	"tbsps":        "tbl",
-	"tbsps.":       "tbl",
-	"teaspoon":     "tsp",
-	"teaspoon.":    "tsp",
-	"teaspoons":    "tsp",
-	"teaspoons.":   "tsp",
-	"tsp":          "tsp",
-	"tsp.":         "tsp",
-	"tsps":         "tsp",
-	"tsps.":        "tsp",
-	"tsps..":       "tsp",
+	"c":                      "cup",
+	"c.":                     "cup",
+	"c..":                    "cup",
+	"can":                    "can",
+	"can.":                   "can",
+	"canned":                 "can",
+	"canned.":                "can",
+	"cans":                   "can",
+	"cans.":                  "can",
+	"cans..":                 "can",
+	"cup":                    "cup",
+	"cup.":                   "cup",
+	"cup..":                  "cup",
+	"cups":                   "cup",
+	"cups.":                  "cup",
+	"g":                      "gram",
+	"g.":                     "gram",
+	"gram":                   "gram",
+	"gram.":                  "gram",
+	"grams":                  "gram",
+	"grams.":                 "gram",
+	"grams..":                "gram",
+	"grams...":               "gram",
+	"imperial fl oz":         "imperial fluid ounce",
+	"imperial fl oz.":        "imperial fluid ounce",
+	"imperial fluid ounce":   "imperial fluid ounce",
+	"imperial fluid ounce.":  "imperial fluid ounce",
+	"imperial fluid ounces":  "imperial fluid ounce",
+	"imperial fluid ounces.": "imperial fluid ounce",
+	"imperial pint":          "imperial pint",
+	"imperial pint.":         "imperial pint",
+	"imperial pints":         "imperial pint",
+	"imperial pints.":        "imperial pint",
+	"kg":                     "kilogram",
+	"kg.":                    "kilogram",
+	"kilogram":               "kilogram",
+	"kilograms":              "kilogram",
+	"l":                      "liter",
+	"l.":                     "liter",
+	"lb":                     "pound",
+	"lb.":                    "pound",
+	"lbs":                    "pound",
+	"lbs.":                   "pound",
+	"liter":                  "liter",
+	"liters":                 "liter",
+	"litre":                  "liter",
+	"litres":                 "liter",
+	"milliliter":             "milliliter",
+	"milliliter.":            "milliliter",
+	"ml":                     "milliliter",
+	"ml.":                    "milliliter",
+	"ounce":                  "ounce",
+	"ounce.":                 "ounce",
+	"ounces":                 "ounce",
+	"ounces.":                "ounce",
+	"ounces..":               "ounce",
+	"oz":                     "ounce",
+	"oz.":                    "ounce",
+	"oz..":                   "ounce",
+	"pint":                   "pint",
+	"pint.":                  "pint",
+	"pint..":                 "pint",
+	"pints":                  "pint",
+	"pints.":                 "pint",
+	"pints..":                "pint",
+	"pound":                  "pound",
+	"pound.":                 "pound",
+	"pounds":                 "pound",
+	"pounds.":                "pound",
+	"quart":                  "quart",
+	"quart.":                 "quart",
+	"quart..":                "quart",
+	"quarts":                 "quart",
+	"quarts.":                "quart",
+	"t":                      "tsp",
+	"t.":                     "tsp",
+	"tablespoon":             "tbl",
+	"tablespoon.":            "tbl",
+	"tablespoon..":           "tbl",
+	"tablespoons":            "tbl",
+	"tablespoons.":           "tbl",
+	"tbl":                    "tbl",
+	"tbl.":                   "tbl",
+	"tbls":                   "tbl",
+	"tbls.":                  "tbl",
+	"tbls..":                 "tbl",
+	"tblsp":                  "tbl",
+	"tblsp.":                 "tbl",
+	"tblsp..":                "tbl",
+	"tblsp...":               "tbl",
+	"tbs":                    "tbl",
+	"tbs.":                   "tbl",
+	"tbsp":                   "tbl",
+	"tbsp.":                  "tbl",
+	"tbsp..":                 "tbl",
+	"tbsps":                  "tbl",
+	"tbsps.":                 "tbl",
+	"teaspoon":               "tsp",
+	"teaspoon.":              "tsp",
+	"teaspoons":              "tsp",
+	"teaspoons.":             "tsp",
+	"tsp":                    "tsp",
+	"tsp.":                   "tsp",
+	"tsps":                   "tsp",
+	"tsps.":                  "tsp",
+	"tsps..":                 "tsp",
 }
 
 var densities = map[string]float64{
@@ -8669,3 +8962,73 @@ var densities = map[string]float64{
 	"yogurt":                 211.8000000000,
 	"zucchini":               194.4000000000,
 }
+
+var corpusSynonyms = map[string]string{
+	"aubergine":           "eggplant",
+	"beetroot":            "beet",
+	"bicarb":              "baking soda",
+	"bicarbonate of soda": "baking soda",
+	"capsicum":            "bell pepper",
+	"caster sugar":        "superfine sugar",
+	"cheddar cheese":      "cheddar",
+	"confectioners sugar": "powdered sugar",
+	"coriander leaf":      "cilantro",
+	"cornflour":           "cornstarch",
+	"courgette":           "zucchini",
+	"double cream":        "heavy cream",
+	"feta cheese":         "feta",
+	"garbanzo bean":       "chickpea",
+	"icing sugar":         "powdered sugar",
+	"mangetout":           "snow pea",
+	"mozzarella cheese":   "mozzarella",
+	"parmesan cheese":     "parmesan",
+	"parmigiano reggiano": "parmesan",
+	"prawn":               "shrimp",
+	"rocket":              "arugula",
+	"scallion":            "green onion",
+	"soda bicarbonate":    "baking soda",
+	"spring onion":        "green onion",
+	"swede":               "rutabaga",
+}
+
+var corpusParents = map[string]string{
+	"aged cheddar":      "cheddar",
+	"baby spinach":      "spinach",
+	"blue cheese":       "cheese",
+	"bread flour":       "flour",
+	"brown sugar":       "sugar",
+	"butter":            "dairy",
+	"buttermilk":        "dairy",
+	"cake flour":        "flour",
+	"canola oil":        "oil",
+	"cheddar":           "cheese",
+	"cheese":            "dairy",
+	"cherry tomato":     "tomato",
+	"chicken breast":    "chicken",
+	"chicken thigh":     "chicken",
+	"cottage cheese":    "cheese",
+	"cream":             "dairy",
+	"cream cheese":      "cheese",
+	"feta":              "cheese",
+	"greek yogurt":      "yogurt",
+	"heavy cream":       "cream",
+	"milk":              "dairy",
+	"mozzarella":        "cheese",
+	"olive oil":         "oil",
+	"parmesan":          "cheese",
+	"powdered sugar":    "sugar",
+	"red onion":         "onion",
+	"ricotta":           "cheese",
+	"roma tomato":       "tomato",
+	"sharp cheddar":     "cheddar",
+	"skim milk":         "milk",
+	"sour cream":        "dairy",
+	"superfine sugar":   "sugar",
+	"vegetable oil":     "oil",
+	"whipping cream":    "cream",
+	"white cheddar":     "cheddar",
+	"white onion":       "onion",
+	"whole wheat flour": "flour",
+	"yellow onion":      "onion",
+	"yogurt":            "dairy",
+}
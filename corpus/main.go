@@ -45,101 +45,50 @@ func main() {
 	var pl pairList
 	var i int
 
-	// MAKE HERBS
-	var herbList []string
-	b, err = os.ReadFile("corpus/herbs.json")
-	if err != nil {
-		panic(err)
-	}
-	if json.Unmarshal(b, &herbList) != nil {
-		panic("could not unmarshal")
-	}
-	f.WriteString(`var herbMap = map[string]struct{}{` + "\n")
-	pl = make(pairList, len(herbList))
-	i = 0
-	for _, k := range herbList {
-		pl[i] = pair{k, len(k)}
-		i++
-	}
-	sort.Slice(pl, func(i, j int) bool {
-		return pl[i].Key < pl[j].Key
-	})
-	for _, p := range pl {
-		f.WriteString(fmt.Sprintf(`"%s": {},`, p.Key) + "\n")
-	}
-	f.WriteString("}\n\n")
+	// MAKE CLASSES
+	herbList := readList("corpus/herbs.json")
+	writeSet(f, "herbMap", herbList)
+	fruitList := readList("corpus/fruits.json")
+	writeSet(f, "fruitMap", fruitList)
+	vegetableList := readList("corpus/vegetables.json")
+	writeSet(f, "vegetableMap", vegetableList)
+	proteinList := readList("corpus/proteins.json")
+	writeSet(f, "proteinMap", proteinList)
 
-	// MAKE FRUITS
-	var fruitList []string
-	b, err = os.ReadFile("corpus/fruits.json")
-	if err != nil {
-		panic(err)
-	}
-	if json.Unmarshal(b, &fruitList) != nil {
-		panic("could not unmarshal")
-	}
-	f.WriteString(`var fruitMap = map[string]struct{}{` + "\n")
-	pl = make(pairList, len(fruitList))
-	i = 0
-	for _, k := range fruitList {
-		pl[i] = pair{k, len(k)}
-		i++
-	}
-	sort.Slice(pl, func(i, j int) bool {
-		return pl[i].Key < pl[j].Key
-	})
-	for _, p := range pl {
-		f.WriteString(fmt.Sprintf(`"%s": {},`, p.Key) + "\n")
+	// MAKE CATEGORIES
+	var categoryLists [][]string
+	for _, category := range []struct{ file, name string }{
+		{"spices", "spiceMap"},
+		{"dairy", "dairyMap"},
+		{"grains", "grainMap"},
+		{"sweeteners", "sweetenerMap"},
+		{"fats", "fatMap"},
+		{"liquids", "liquidMap"},
+	} {
+		list := readList("corpus/" + category.file + ".json")
+		writeSet(f, category.name, list)
+		categoryLists = append(categoryLists, list)
 	}
-	f.WriteString("}\n\n")
 
-	// MAKE VEGETABLES
-	var vegetableList []string
-	b, err = os.ReadFile("corpus/vegetables.json")
+	// MAKE PREPARATIONS
+	writeSet(f, "preparationMap", readList("corpus/preparations.json"))
+
+	// SYNONYMS AND PARENTS
+	var synonyms, parents map[string]string
+	b, err = os.ReadFile("corpus/synonyms.json")
 	if err != nil {
 		panic(err)
 	}
-	if json.Unmarshal(b, &vegetableList) != nil {
+	if json.Unmarshal(b, &synonyms) != nil {
 		panic("could not unmarshal")
 	}
-	f.WriteString(`var vegetableMap = map[string]struct{}{` + "\n")
-	pl = make(pairList, len(vegetableList))
-	i = 0
-	for _, k := range vegetableList {
-		pl[i] = pair{k, len(k)}
-		i++
-	}
-	sort.Slice(pl, func(i, j int) bool {
-		return pl[i].Key < pl[j].Key
-	})
-	for _, p := range pl {
-		f.WriteString(fmt.Sprintf(`"%s": {},`, p.Key) + "\n")
-	}
-	f.WriteString("}\n\n")
-
-	// MAKE PROTEINS
-	var proteinList []string
-	b, err = os.ReadFile("corpus/proteins.json")
+	b, err = os.ReadFile("corpus/parents.json")
 	if err != nil {
 		panic(err)
 	}
-	if json.Unmarshal(b, &proteinList) != nil {
+	if json.Unmarshal(b, &parents) != nil {
 		panic("could not unmarshal")
 	}
-	f.WriteString(`var proteinMap = map[string]struct{}{` + "\n")
-	pl = make(pairList, len(proteinList))
-	i = 0
-	for _, k := range proteinList {
-		pl[i] = pair{k, len(k)}
-		i++
-	}
-	sort.Slice(pl, func(i, j int) bool {
-		return pl[i].Key < pl[j].Key
-	})
-	for _, p := range pl {
-		f.WriteString(fmt.Sprintf(`"%s": {},`, p.Key) + "\n")
-	}
-	f.WriteString("}\n\n")
 
 	// MAIN INGREDIENT LIST
 	// sort the ingredient corpus by the length of each term
@@ -162,6 +111,15 @@ func main() {
 	for _, ing := range proteinList {
 		addIngredientWithPlural(ingredientSizes, ing)
 	}
+	for _, list := range categoryLists {
+		for _, ing := range list {
+			addIngredientWithPlural(ingredientSizes, ing)
+		}
+	}
+	for k, v := range synonyms {
+		addIngredientWithPlural(ingredientSizes, k)
+		addIngredientWithPlural(ingredientSizes, v)
+	}
 
 	pl = make(pairList, len(ingredientSizes))
 	i = 0
@@ -329,6 +287,45 @@ func main() {
 	}
 	f.WriteString("}\n\n")
 
+	writeStringMap(f, "corpusSynonyms", synonyms)
+	writeStringMap(f, "corpusParents", parents)
+}
+
+// readList reads a JSON list of names
+func readList(fname string) (list []string) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		panic(err)
+	}
+	if json.Unmarshal(b, &list) != nil {
+		panic("could not unmarshal")
+	}
+	return
+}
+
+// writeSet writes a set of names sorted alphabetically
+func writeSet(f *os.File, name string, list []string) {
+	sorted := append([]string{}, list...)
+	sort.Strings(sorted)
+	f.WriteString(`var ` + name + ` = map[string]struct{}{` + "\n")
+	for _, k := range sorted {
+		f.WriteString(fmt.Sprintf(`"%s": {},`, k) + "\n")
+	}
+	f.WriteString("}\n\n")
+}
+
+// writeStringMap writes a map of strings sorted by key
+func writeStringMap(f *os.File, name string, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	f.WriteString(`var ` + name + ` = map[string]string{` + "\n")
+	for _, k := range keys {
+		f.WriteString(fmt.Sprintf(`"%s": "%s",`, k, m[k]) + "\n")
+	}
+	f.WriteString("}\n\n")
 }
 
 type pair struct {
@@ -351,6 +348,7 @@ var corpusFractionNumberMap = map[string]fractionNumber{
 	"½": {"1/2", 1.0 / 2},
 	"¼": {"1/4", 1.0 / 4},
 	"¾": fractionNumber{"3/4", 3.0 / 4},
+	"⅙": fractionNumber{"1/6", 1.0 / 6},
 	"⅛": fractionNumber{"1/8", 1.0 / 8},
 	"⅜": fractionNumber{"3/8", 3.0 / 8},
 	"⅝": fractionNumber{"5/8", 5.0 / 8},
@@ -382,15 +380,32 @@ var corpusMeasuresMap = map[string]string{
 	"grams":       "gram",
 	"g":           "gram",
 	"gram":        "gram",
+	"kg":          "kilogram",
+	"kilogram":    "kilogram",
+	"kilograms":   "kilogram",
 	"milliliter":  "milliliter",
 	"ml":          "milliliter",
+	"l":           "liter",
+	"liter":       "liter",
+	"liters":      "liter",
+	"litre":       "liter",
+	"litres":      "liter",
 	"pint":        "pint",
 	"pints":       "pint",
 	"quart":       "quart",
 	"quarts":      "quart",
 	"pound":       "pound",
 	"pounds":      "pound",
+	"lb":          "pound",
+	"lbs":         "pound",
 	"cans":        "can",
 	"canned":      "can",
 	"can":         "can",
+
+	// imperial measures, which differ from the US ones of the same name
+	"imperial fluid ounce":  "imperial fluid ounce",
+	"imperial fluid ounces": "imperial fluid ounce",
+	"imperial fl oz":        "imperial fluid ounce",
+	"imperial pint":         "imperial pint",
+	"imperial pints":        "imperial pint",
 }
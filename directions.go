@@ -0,0 +1,303 @@
+package ingredients
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/astappiev/microdata"
+	json "github.com/goccy/go-json"
+	log "github.com/schollz/logger"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Step is a single instruction in the recipe directions
+type Step struct {
+	Text    string `json:"text"`
+	Section string `json:"section,omitempty"`
+	Source  string `json:"source,omitempty"` // "schema.org" or "dom"
+}
+
+var (
+	directionsTrie    *Trie
+	directionsNegTrie *Trie
+)
+
+func init() {
+	directionsTrie = newTrie(corpusDirections)
+	directionsNegTrie = newTrie(corpusDirectionsNeg)
+}
+
+// getDirectionsInHTML returns the recipe directions, preferring the schema.org
+// recipeInstructions and falling back to scoring the paragraphs and list items
+func getDirectionsInHTML(htmlS string, schemaRecipe map[string]interface{}) (steps []Step) {
+	if schemaRecipe != nil {
+		steps = parseInstructions(schemaRecipe["recipeInstructions"], "")
+		if len(steps) > 0 {
+			log.Tracef("extracted %d directions from schema.org Recipe", len(steps))
+			return
+		}
+	}
+
+	doc, err := html.Parse(bytes.NewReader([]byte(htmlS)))
+	if err != nil {
+		return
+	}
+	return getDirectionsInDOM(doc)
+}
+
+// parseInstructions converts a schema.org recipeInstructions value into steps.
+// It handles plain strings, lists, HowToStep and HowToSection.
+func parseInstructions(v interface{}, section string) (steps []Step) {
+	switch val := v.(type) {
+	case string:
+		for _, line := range strings.Split(stripTags(val), "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), ",;"))
+			if line == "" {
+				continue
+			}
+			steps = append(steps, Step{Text: line, Section: section, Source: "schema.org"})
+		}
+	case []interface{}:
+		for _, item := range val {
+			steps = append(steps, parseInstructions(item, section)...)
+		}
+	case map[string]interface{}:
+		if hasSchemaType(val, "HowToSection") {
+			name, _ := val["name"].(string)
+			return parseInstructions(val["itemListElement"], strings.TrimSpace(name))
+		}
+		if text, ok := val["text"].(string); ok && strings.TrimSpace(text) != "" {
+			return parseInstructions(text, section)
+		}
+		if _, ok := val["itemListElement"]; ok {
+			return parseInstructions(val["itemListElement"], section)
+		}
+		if name, ok := val["name"].(string); ok {
+			return parseInstructions(name, section)
+		}
+	}
+	return
+}
+
+// getDirectionsInDOM finds the block of paragraphs or list items that
+// scores highest against the directions corpus
+func getDirectionsInDOM(doc *html.Node) (steps []Step) {
+	bestScore := 0
+	var f func(n *html.Node)
+	f = func(n *html.Node) {
+		if n.DataAtom == atom.Script || n.DataAtom == atom.Style {
+			return
+		}
+		score := 0
+		childSteps := []Step{}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+			if c.DataAtom != atom.Li && c.DataAtom != atom.P {
+				continue
+			}
+			text := nodeText(c)
+			scoreOfStep := scoreDirection(text)
+			if scoreOfStep <= 0 {
+				continue
+			}
+			// ordered lists are almost always steps
+			if n.DataAtom == atom.Ol {
+				scoreOfStep++
+			}
+			score += scoreOfStep
+			childSteps = append(childSteps, Step{Text: text, Source: "dom"})
+		}
+		if len(childSteps) >= 2 && score > bestScore {
+			bestScore = score
+			steps = childSteps
+		}
+	}
+	f(doc)
+	if bestScore < 5 {
+		steps = nil
+	}
+	return
+}
+
+// scoreDirection scores how much a line reads like a recipe direction
+func scoreDirection(line string) (score int) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(line) > 1000 {
+		return
+	}
+	words := directionWords(fields)
+	score += len(directionsTrie.findAll(words))
+	score -= len(directionsNegTrie.findAll(words))
+
+	// directions are sentences
+	if strings.ContainsAny(line, ".!") {
+		score++
+	}
+
+	// disfavor lines that look like ingredients
+	if ingredientScore, _ := scoreLine(line); ingredientScore > 3 {
+		score -= ingredientScore
+	}
+	return
+}
+
+// directionWords lowercases and pads the words of a line so that each word
+// can match the space-padded corpus entries independently
+func directionWords(fields []string) string {
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		word := strings.Trim(strings.ToLower(field), `.,;:!?()"'`)
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return " " + strings.Join(words, "  ") + " "
+}
+
+// nodeText returns the whitespace-collapsed text inside a node
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var f func(n *html.Node)
+	f = func(n *html.Node) {
+		if n.DataAtom == atom.Script || n.DataAtom == atom.Style {
+			return
+		}
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// stripTags removes any HTML markup from a string, keeping line breaks
+func stripTags(s string) string {
+	if !strings.Contains(s, "<") {
+		return html.UnescapeString(s)
+	}
+	s = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n", "</li>", "\n").Replace(s)
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return s
+	}
+	var sb strings.Builder
+	var f func(n *html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	for _, n := range nodes {
+		f(n)
+	}
+	return sb.String()
+}
+
+// findSchemaRecipe returns the schema.org Recipe in the HTML as a JSON-like map.
+// It checks JSON-LD and microdata first and then any JSON found in script tags.
+func findSchemaRecipe(htmlS string) (recipe map[string]interface{}, ok bool) {
+	data, err := microdata.ParseHTML(strings.NewReader(htmlS), "", "")
+	if err == nil {
+		if item := data.GetFirstOfSchemaType("Recipe"); item != nil {
+			return itemToMap(item), true
+		}
+	}
+
+	doc, err := html.Parse(bytes.NewReader([]byte(htmlS)))
+	if err != nil {
+		return
+	}
+	var f func(n *html.Node)
+	f = func(n *html.Node) {
+		if ok {
+			return
+		}
+		if n.DataAtom == atom.Script && n.FirstChild != nil {
+			var v interface{}
+			if json.Unmarshal([]byte(n.FirstChild.Data), &v) == nil {
+				recipe, ok = findRecipeInJSON(v)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(doc)
+	return
+}
+
+// findRecipeInJSON walks decoded JSON looking for an object of type Recipe
+func findRecipeInJSON(v interface{}) (recipe map[string]interface{}, ok bool) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if hasSchemaType(val, "Recipe") {
+			return val, true
+		}
+		for _, child := range val {
+			if recipe, ok = findRecipeInJSON(child); ok {
+				return
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if recipe, ok = findRecipeInJSON(child); ok {
+				return
+			}
+		}
+	}
+	return
+}
+
+// hasSchemaType reports whether the "@type" of a JSON-like map matches
+func hasSchemaType(m map[string]interface{}, schemaType string) bool {
+	var types []interface{}
+	switch t := m["@type"].(type) {
+	case string:
+		types = []interface{}{t}
+	case []interface{}:
+		types = t
+	}
+	for _, t := range types {
+		s, _ := t.(string)
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "http://schema.org/"), "https://schema.org/")
+		if s == schemaType {
+			return true
+		}
+	}
+	return false
+}
+
+// itemToMap converts a microdata item into the same shape as decoded JSON-LD
+func itemToMap(item *microdata.Item) map[string]interface{} {
+	m := make(map[string]interface{}, len(item.Properties)+1)
+	types := make([]interface{}, len(item.Types))
+	for i, t := range item.Types {
+		types[i] = t
+	}
+	m["@type"] = types
+	for key, values := range item.Properties {
+		converted := make([]interface{}, len(values))
+		for i, v := range values {
+			if nested, ok := v.(*microdata.Item); ok {
+				converted[i] = itemToMap(nested)
+			} else {
+				converted[i] = v
+			}
+		}
+		if len(converted) == 1 {
+			m[key] = converted[0]
+		} else {
+			m[key] = converted
+		}
+	}
+	return m
+}
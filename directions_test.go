@@ -0,0 +1,74 @@
+package ingredients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectionsSchemaOrg(t *testing.T) {
+	htmlString := `<html><head><script type="application/ld+json">
+{
+	"@context": "https://schema.org",
+	"@type": "Recipe",
+	"name": "Layer Cake",
+	"recipeIngredient": ["2 cups flour", "1 cup sugar", "1/2 cup butter"],
+	"recipeInstructions": [
+		{
+			"@type": "HowToSection",
+			"name": "For the cake",
+			"itemListElement": [
+				{"@type": "HowToStep", "text": "Preheat the oven to 350 degrees."},
+				{"@type": "HowToStep", "text": "Mix the flour and sugar."}
+			]
+		},
+		{
+			"@type": "HowToSection",
+			"name": "For the frosting",
+			"itemListElement": [
+				{"@type": "HowToStep", "text": "Beat the butter until fluffy."}
+			]
+		},
+		"Frost the cooled cake."
+	]
+}
+</script></head><body></body></html>`
+	r, err := NewFromHTML("test", htmlString)
+	assert.Nil(t, err)
+	assert.Equal(t, []Step{
+		{Text: "Preheat the oven to 350 degrees.", Section: "For the cake", Source: "schema.org"},
+		{Text: "Mix the flour and sugar.", Section: "For the cake", Source: "schema.org"},
+		{Text: "Beat the butter until fluffy.", Section: "For the frosting", Source: "schema.org"},
+		{Text: "Frost the cooled cake.", Source: "schema.org"},
+	}, r.Directions)
+}
+
+func TestDirectionsDOM(t *testing.T) {
+	htmlString := `<html><body>
+	<ul>
+		<li>2 cups flour</li>
+		<li>1 cup sugar</li>
+		<li>1/2 cup butter, melted</li>
+		<li>2 eggs</li>
+	</ul>
+	<ol>
+		<li>Preheat the oven to 375 degrees F.</li>
+		<li>In a large bowl, stir the melted butter and sugar until combined.</li>
+		<li>Add the eggs and mix well, then stir in the flour.</li>
+		<li>Bake for 10 minutes and let cool completely on the sheet.</li>
+	</ol>
+	<p>I also recommend these cookies for dessert, they are the best ever!</p>
+	</body></html>`
+	r, err := NewFromHTML("test", htmlString)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(r.Directions))
+	assert.Equal(t, "Preheat the oven to 375 degrees F.", r.Directions[0].Text)
+	assert.Equal(t, "dom", r.Directions[0].Source)
+}
+
+func TestDirectionsTable(t *testing.T) {
+	r, err := NewFromFile("testing/sites/joyfoodsunshine.com/the-most-amazing-chocolate-chip-cookies/index.html")
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(r.Directions))
+	assert.Equal(t, "Preheat oven to 375 degrees F. Line a baking pan with parchment paper and set aside.", r.Directions[0].Text)
+}
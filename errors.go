@@ -0,0 +1,97 @@
+package ingredients
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors that can be checked with errors.Is. Most are wrapped with the
+// value that caused them, e.g. "could not find 'cupz'".
+var (
+	// ErrNoContent means a recipe has no HTML or file to parse
+	ErrNoContent = errors.New("no file loaded")
+	// ErrNoRecipe means a page has no recipe, either because it has no
+	// ingredients or too few of them, see InsufficientIngredientsError
+	ErrNoRecipe = errors.New("no recipe found")
+	// ErrNoAmount and ErrNoIngredient mean a line is not an ingredient
+	ErrNoAmount     = errors.New("no amount found")
+	ErrNoIngredient = errors.New("no ingredient found")
+	// ErrNotFound means a measure or an ingredient is not known
+	ErrNotFound = errors.New("could not find")
+	// ErrNotConvertible means an amount cannot be converted to cups
+	ErrNotConvertible = errors.New("could not convert weight or volume")
+	// ErrNotWeighable means an ingredient cannot be converted to grams
+	ErrNotWeighable = errors.New("could not weigh")
+	// ErrIncompatibleUnits means two measures cannot be compared
+	ErrIncompatibleUnits = errors.New("cannot compare")
+	// ErrInvalidScale means a recipe cannot be scaled by a factor
+	ErrInvalidScale = errors.New("cannot scale by")
+	// ErrNoYield means a recipe has no yield to scale by servings
+	ErrNoYield = errors.New("recipe has no yield to scale from")
+	// ErrUnknownUnitSystem means measures cannot be converted to a UnitSystem
+	ErrUnknownUnitSystem = errors.New("cannot convert to unit system")
+	// ErrUnknownUnit means a measure of a corpus has a unit that is not known
+	ErrUnknownUnit = errors.New("unknown unit")
+	// ErrInvalidDuration means a duration is not in ISO-8601
+	ErrInvalidDuration = errors.New("could not parse duration")
+	// ErrBodyTooLarge means a fetched page is larger than the maximum body
+	// size, see WithMaxBodySize
+	ErrBodyTooLarge = errors.New("response body too large")
+	// ErrEmptySource means a Source of ParseBatch has nothing to parse
+	ErrEmptySource = errors.New("source has no url, file or HTML")
+)
+
+// InsufficientIngredientsError means a page has too few ingredients to be
+// a recipe. It is also ErrNoRecipe.
+type InsufficientIngredientsError struct {
+	Found   int
+	Minimum int
+}
+
+func (e *InsufficientIngredientsError) Error() string {
+	return fmt.Sprintf("insufficient ingredients found: %d (minimum %d required)", e.Found, e.Minimum)
+}
+
+func (e *InsufficientIngredientsError) Unwrap() error {
+	return ErrNoRecipe
+}
+
+// FetchError means a recipe could not be fetched from a url. It wraps the
+// error of the request, e.g. a context.DeadlineExceeded.
+type FetchError struct {
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("could not fetch %s: %s", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError means a server answered with a status that is not 2xx
+type StatusError struct {
+	StatusCode int
+	Status     string
+	// RetryAfter is how long the server asked to wait before trying again,
+	// 0 if it did not say
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return "unexpected status " + e.Status
+}
+
+// DisallowedError means the robots.txt of a site does not allow a url to
+// be fetched by the user agent of the Parser
+type DisallowedError struct {
+	URL       string
+	UserAgent string
+}
+
+func (e *DisallowedError) Error() string {
+	return fmt.Sprintf("robots.txt disallows %s for %q", e.URL, e.UserAgent)
+}
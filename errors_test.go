@@ -0,0 +1,60 @@
+package ingredients
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsNoRecipe(t *testing.T) {
+	htmlS := `<html><body><ul><li>1 cup flour</li><li>2 eggs</li></ul></body></html>`
+	_, err := NewParser(WithMinIngredients(3)).NewFromHTML("test", htmlS)
+	assert.True(t, errors.Is(err, ErrNoRecipe))
+	var insufficient *InsufficientIngredientsError
+	assert.True(t, errors.As(err, &insufficient))
+	assert.Equal(t, 2, insufficient.Found)
+	assert.Equal(t, 3, insufficient.Minimum)
+	assert.Equal(t, "insufficient ingredients found: 2 (minimum 3 required)", err.Error())
+}
+
+func TestErrorsFile(t *testing.T) {
+	r, err := NewFromFile("testing/does-not-exist.html")
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestErrorsFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewFromURLWithContext(ctx, server.URL)
+	var fetchErr *FetchError
+	assert.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, server.URL, fetchErr.URL)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestErrorsScale(t *testing.T) {
+	r := parseLines(t, "2 cups flour\n2 eggs")
+	_, err := r.Scale(-1)
+	assert.True(t, errors.Is(err, ErrInvalidScale))
+	_, err = r.ScaleToServings(2)
+	assert.True(t, errors.Is(err, ErrNoYield))
+	_, err = r.ScaleToIngredient("saffron", 1, "teaspoon")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, "could not find 'saffron'", err.Error())
+	_, err = r.ScaleToIngredient("flour", 2, "whole")
+	assert.True(t, errors.Is(err, ErrIncompatibleUnits))
+
+	err = r.ConvertTo(UnitSystem(99))
+	assert.True(t, errors.Is(err, ErrUnknownUnitSystem))
+}
@@ -0,0 +1,41 @@
+package ingredients
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explain describes how each candidate line of the recipe was read: the
+// ingredient that was found in it or why it was rejected, with the
+// confidence that it is an ingredient and what lowered it
+func (r *Recipe) Explain() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d lines parsed, %d rejected\n", len(r.Lines), len(r.Rejected))
+	for _, line := range r.Lines {
+		ing := line.Ingredient
+		fmt.Fprintf(&sb, "\n+ %q (%s, confidence %.2f)\n", strings.TrimSpace(line.LineOriginal), line.Source, line.Confidence)
+		fmt.Fprintf(&sb, "  ingredient: %s\n", ing.Name)
+		if ing.ID != ing.Name {
+			fmt.Fprintf(&sb, "  id: %s\n", ing.ID)
+		}
+		fmt.Fprintf(&sb, "  measure: %s %s\n", ing.Measure.AmountString(), ing.Measure.Name)
+		for _, alternative := range ing.Alternatives {
+			fmt.Fprintf(&sb, "  or: %s\n", alternative.Name)
+		}
+		if line.Fuzzy != nil {
+			fmt.Fprintf(&sb, "  corrected: %q to %q (%.2f)\n", line.Fuzzy.Original, line.Fuzzy.Corrected, line.Fuzzy.Score)
+		}
+		writeReasons(&sb, line.Reasons)
+	}
+	for _, line := range r.Rejected {
+		fmt.Fprintf(&sb, "\n- %q (%s, confidence %.2f)\n", strings.TrimSpace(line.LineOriginal), line.Source, line.Confidence)
+		writeReasons(&sb, line.Reasons)
+	}
+	return sb.String()
+}
+
+func writeReasons(sb *strings.Builder, reasons []string) {
+	for _, reason := range reasons {
+		fmt.Fprintf(sb, "  - %s\n", reason)
+	}
+}
@@ -0,0 +1,89 @@
+package ingredients
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	r := parseLines(t, `2 cups flour
+2 eggs
+Serving size: 1 cookie
+a pinch of love
+1 cup sugar or honey`)
+	assert.Equal(t, 3, len(r.Lines))
+	assert.Equal(t, 1.0, r.Lines[0].Confidence)
+	assert.Nil(t, r.Lines[0].Reasons)
+	assert.Equal(t, 0.5, r.Lines[1].Confidence)
+	assert.Equal(t, []string{"no measure found"}, r.Lines[1].Reasons)
+
+	assert.Equal(t, 2, len(r.Rejected))
+	assert.Equal(t, "Serving size: 1 cookie", r.Rejected[0].LineOriginal)
+	assert.Contains(t, r.Rejected[0].Reasons, "serving size")
+	assert.Equal(t, "a pinch of love", r.Rejected[1].LineOriginal)
+	assert.Equal(t, []string{"no ingredient found", "no amount found", "no measure found"}, r.Rejected[1].Reasons)
+	assert.Equal(t, 0.0, r.Rejected[1].Confidence)
+
+	assert.Equal(t, `3 lines parsed, 2 rejected
+
++ "2 cups flour" (dom, confidence 1.00)
+  ingredient: flour
+  measure: 2 cups
+
++ "2 eggs" (dom, confidence 0.50)
+  ingredient: egg
+  measure: 2 whole
+  - no measure found
+
++ "1 cup sugar or honey" (dom, confidence 1.00)
+  ingredient: sugar
+  measure: 1 cup
+  or: honey
+
+- "Serving size: 1 cookie" (dom, confidence 0.00)
+  - no ingredient found
+  - no measure found
+  - only one sign of an ingredient
+  - serving size
+
+- "a pinch of love" (dom, confidence 0.00)
+  - no ingredient found
+  - no amount found
+  - no measure found
+`, r.Explain())
+}
+
+func TestScoreLineReasons(t *testing.T) {
+	score, lineInfo := scoreLine("flour, 2 cups")
+	assert.Equal(t, 4, score)
+	assert.InDelta(t, 4.0/6, lineInfo.Confidence, 1e-9)
+	assert.Equal(t, []string{"ingredient before the measure", "ingredient before the amount"}, lineInfo.Reasons)
+
+	// long lines do not count toward finding the ingredients, but are
+	// still scored
+	score, lineInfo = scoreLine("2 cups all-purpose flour, sifted twice and then measured again")
+	assert.Equal(t, 0, score)
+	assert.Equal(t, []string{"long line (63 characters)"}, lineInfo.Reasons)
+}
+
+func TestAcceptedLinesConfidence(t *testing.T) {
+	r := parseLines(t, `2 cups all-purpose flour, sifted twice and then measured again
+1 cup sugar
+salt and pepper to taste`)
+	assert.Equal(t, 4, len(r.Lines))
+	for _, t0 := range ts {
+		fileToGet := strings.TrimPrefix(t0.URL, "https://")
+		if strings.HasSuffix(fileToGet, "/") {
+			fileToGet += "index.html"
+		}
+		site, err := NewFromFile(path.Join("testing", "sites", fileToGet))
+		assert.Nil(t, err)
+		r.Lines = append(r.Lines, site.Lines...)
+	}
+	for _, line := range r.Lines {
+		assert.Greater(t, line.Confidence, 0.0, line.LineOriginal)
+	}
+}
@@ -0,0 +1,225 @@
+package ingredients
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Extractor finds the ingredient lines on a particular kind of page. It is
+// consulted before the generic schema.org, JSON and DOM heuristics. Lines
+// ending in ":" like "For the frosting:" are taken as group titles.
+type Extractor interface {
+	// Name identifies the extractor and is used as the Source of its lines
+	Name() string
+	// Extract returns the ingredient lines of the page
+	Extract(doc *html.Node) (lines []string, err error)
+}
+
+// extractorRule is a registered extractor with what it applies to
+type extractorRule struct {
+	hostname  string
+	match     func(htmlS string) bool
+	extractor Extractor
+}
+
+var extractorRegistry struct {
+	sync.RWMutex
+	rules []extractorRule
+}
+
+func init() {
+	for _, e := range []*classExtractor{
+		{name: "wprm", container: "wprm-recipe-ingredients-container", item: "wprm-recipe-ingredient", header: "wprm-recipe-group-name", notes: "wprm-recipe-ingredient-notes"},
+		{name: "tasty-recipes", container: "tasty-recipes-ingredients"},
+		{name: "mv-create", container: "mv-create-ingredients"},
+	} {
+		RegisterExtractor(e.matches, e)
+	}
+}
+
+// RegisterHostExtractor uses an extractor for every page of a host and its
+// subdomains, e.g. "example.com" also covers "www.example.com"
+func RegisterHostExtractor(hostname string, e Extractor) {
+	extractorRegistry.Lock()
+	defer extractorRegistry.Unlock()
+	hostname = strings.TrimPrefix(strings.ToLower(hostname), "www.")
+	extractorRegistry.rules = append(extractorRegistry.rules, extractorRule{hostname: hostname, extractor: e})
+}
+
+// RegisterExtractor uses an extractor for every page whose HTML matches
+func RegisterExtractor(match func(htmlS string) bool, e Extractor) {
+	extractorRegistry.Lock()
+	defer extractorRegistry.Unlock()
+	extractorRegistry.rules = append(extractorRegistry.rules, extractorRule{match: match, extractor: e})
+}
+
+// extractorsFor returns the extractors that apply to a page. Extractors
+// for the host come first and later registrations take precedence, so an
+// application can replace the built-in extractors.
+func extractorsFor(hostname, htmlS string) (extractors []Extractor) {
+	extractorRegistry.RLock()
+	defer extractorRegistry.RUnlock()
+	hostname = strings.TrimPrefix(strings.ToLower(hostname), "www.")
+	var byPredicate []Extractor
+	for i := len(extractorRegistry.rules) - 1; i >= 0; i-- {
+		rule := extractorRegistry.rules[i]
+		if rule.hostname != "" {
+			if hostname != "" && (hostname == rule.hostname || strings.HasSuffix(hostname, "."+rule.hostname)) {
+				extractors = append(extractors, rule.extractor)
+			}
+		} else if rule.match != nil && rule.match(htmlS) {
+			byPredicate = append(byPredicate, rule.extractor)
+		}
+	}
+	return append(extractors, byPredicate...)
+}
+
+// extractLinesWithExtractors returns the lines of the first extractor
+// that finds at least two of them
+func (p *Parser) extractLinesWithExtractors(hostname, htmlS string) (lineInfos []LineInfo, ok bool) {
+	extractors := extractorsFor(hostname, htmlS)
+	if len(extractors) == 0 {
+		return
+	}
+	doc, err := html.Parse(strings.NewReader(htmlS))
+	if err != nil {
+		return
+	}
+	for _, e := range extractors {
+		lines, err := e.Extract(doc)
+		if err != nil {
+			p.logger.Tracef("extractor %s: %s", e.Name(), err.Error())
+			continue
+		}
+		lineInfos = lineInfos[:0]
+		for _, line := range lines {
+			_, lineInfo := p.scoreLine(line)
+			lineInfo.Source = e.Name()
+			lineInfos = append(lineInfos, lineInfo)
+		}
+		lineInfos = assignGroups(lineInfos, "")
+		if len(lineInfos) >= 2 {
+			p.logger.Tracef("using extractor %s", e.Name())
+			return lineInfos, true
+		}
+	}
+	return nil, false
+}
+
+// hostnameOf returns the hostname of a recipe's url, if it has one
+func hostnameOf(name string) string {
+	u, err := url.Parse(name)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// classExtractor reads the ingredients of the recipe card plugins, which
+// mark up a container of ingredients with a class. Items are the list
+// items (or elements with the item class) and headers are the headings
+// (or elements with the header class) inside the container.
+type classExtractor struct {
+	name      string
+	container string
+	item      string
+	header    string
+	notes     string
+}
+
+func (e *classExtractor) Name() string {
+	return e.name
+}
+
+func (e *classExtractor) matches(htmlS string) bool {
+	return strings.Contains(htmlS, e.container)
+}
+
+func (e *classExtractor) Extract(doc *html.Node) (lines []string, err error) {
+	var walk func(n *html.Node, inside bool)
+	walk = func(n *html.Node, inside bool) {
+		if n.Type == html.ElementNode {
+			switch {
+			case !inside && hasClass(n, e.container):
+				inside = true
+			case inside && e.isHeader(n):
+				if title := nodeText(n); title != "" {
+					lines = append(lines, strings.TrimSuffix(title, ":")+":")
+				}
+				return
+			case inside && e.isItem(n):
+				if line := e.itemText(n); line != "" {
+					lines = append(lines, line)
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inside)
+		}
+	}
+	walk(doc, false)
+	return
+}
+
+func (e *classExtractor) isItem(n *html.Node) bool {
+	if e.item != "" {
+		return hasClass(n, e.item)
+	}
+	return n.DataAtom == atom.Li
+}
+
+func (e *classExtractor) isHeader(n *html.Node) bool {
+	if e.header != "" {
+		return hasClass(n, e.header)
+	}
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return true
+	}
+	return false
+}
+
+// itemText returns the text of an item with its notes in parentheses.
+// Unlike nodeText it keeps inline markup like "<span>1 cup</span>s" together.
+func (e *classExtractor) itemText(n *html.Node) string {
+	var sb strings.Builder
+	var f func(n *html.Node)
+	f = func(n *html.Node) {
+		if n.DataAtom == atom.Script || n.DataAtom == atom.Style {
+			return
+		}
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		if e.notes != "" && n.Type == html.ElementNode && hasClass(n, e.notes) {
+			if notes := strings.Trim(nodeText(n), "() "); notes != "" {
+				sb.WriteString(" (" + notes + ")")
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// hasClass reports whether an element has a class
+func hasClass(n *html.Node, class string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "class" {
+			for _, c := range strings.Fields(attr.Val) {
+				if c == class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package ingredients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+func TestExtractorsBuiltin(t *testing.T) {
+	tests := []struct {
+		file   string
+		source string
+	}{
+		{"testing/sites/joyfoodsunshine.com/the-most-amazing-chocolate-chip-cookies/index.html", "wprm"},
+		{"testing/sites/pinchofyum.com/the-best-soft-chocolate-chip-cookies", "tasty-recipes"},
+		{"testing/sites/www.realmomnutrition.com/banana-bread-with-chocolate-chips/index.html", "mv-create"},
+	}
+	for _, test := range tests {
+		r, err := NewFromFile(test.file)
+		assert.Nil(t, err, test.file)
+		if !assert.NotEmpty(t, r.Lines, test.file) {
+			continue
+		}
+		for _, line := range r.Lines {
+			assert.Equal(t, test.source, line.Source, line.LineOriginal)
+		}
+	}
+}
+
+func TestTastyRecipesInlineUnits(t *testing.T) {
+	r, err := NewFromFile("testing/sites/pinchofyum.com/the-best-soft-chocolate-chip-cookies")
+	assert.Nil(t, err)
+	assert.Equal(t, "8 tablespoons of salted butter", r.Lines[0].LineOriginal)
+}
+
+type staticExtractor []string
+
+func (e staticExtractor) Name() string { return "static" }
+
+func (e staticExtractor) Extract(doc *html.Node) ([]string, error) { return e, nil }
+
+// restoreExtractors removes the extractors that a test registers once it ends
+func restoreExtractors(t *testing.T) {
+	extractorRegistry.RLock()
+	rules := append([]extractorRule(nil), extractorRegistry.rules...)
+	extractorRegistry.RUnlock()
+	t.Cleanup(func() {
+		extractorRegistry.Lock()
+		extractorRegistry.rules = rules
+		extractorRegistry.Unlock()
+	})
+}
+
+func TestRegisterHostExtractor(t *testing.T) {
+	restoreExtractors(t)
+	RegisterHostExtractor("extractor.example", staticExtractor{"For the cake:", "2 cups flour", "1 cup sugar"})
+	page := "<html><body><p>nothing to see</p></body></html>"
+
+	r, err := NewFromHTML("https://www.extractor.example/cake", page)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(r.Lines))
+	assert.Equal(t, "static", r.Lines[0].Source)
+	assert.Equal(t, "For the cake", r.Lines[0].Ingredient.Group)
+
+	_, err = NewFromHTML("https://other.example/cake", page)
+	assert.NotNil(t, err)
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	restoreExtractors(t)
+	RegisterExtractor(func(htmlS string) bool {
+		return strings.Contains(htmlS, "my-recipe-card")
+	}, staticExtractor{"3 eggs", "1 cup milk"})
+
+	r, err := NewFromHTML("card", `<html><body><div class="my-recipe-card"></div></body></html>`)
+	assert.Nil(t, err)
+	assert.Equal(t, "egg", r.Ingredients[0].Name)
+	assert.Equal(t, "milk", r.Ingredients[1].Name)
+}
+
+func TestExtractorsForPrecedence(t *testing.T) {
+	restoreExtractors(t)
+	RegisterHostExtractor("precedence.example", staticExtractor{"first"})
+	RegisterHostExtractor("precedence.example", staticExtractor{"second"})
+	extractors := extractorsFor("www.precedence.example", `<div class="wprm-recipe-ingredients-container">`)
+	assert.Equal(t, 3, len(extractors))
+	assert.Equal(t, staticExtractor{"second"}, extractors[0])
+	assert.Equal(t, "wprm", extractors[2].Name())
+}
+
+func TestRestoreExtractors(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		restoreExtractors(t)
+		RegisterHostExtractor("restore.example", staticExtractor{"1 cup flour"})
+		assert.Equal(t, 1, len(extractorsFor("restore.example", "")))
+	})
+	assert.Equal(t, 0, len(extractorsFor("restore.example", "")))
+}
@@ -0,0 +1,154 @@
+package ingredients
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// DefaultUserAgent is sent with every request unless it is changed with
+// WithHeader
+const DefaultUserAgent = "Mozilla/5.0 (compatible; ingredients/1.0; +https://github.com/schollz/ingredients)"
+
+// DefaultMaxBodySize is the largest page that is read, 10 MB
+const DefaultMaxBodySize = 10 << 20
+
+// defaultHeaders are sent with every request
+func defaultHeaders() http.Header {
+	return http.Header{
+		"User-Agent":      {DefaultUserAgent},
+		"Accept":          {"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+		"Accept-Language": {"en-US,en;q=0.9"},
+	}
+}
+
+// fetch gets the page at a url as UTF-8, with the url it was redirected
+// to. Pages that are not 2xx, or larger than the maximum body size, are
+// errors. Failures that may pass are retried with the RetryPolicy of the
+// Parser, and with WithRobots the robots.txt of the site is obeyed.
+func (p *Parser) fetch(ctx context.Context, url string) (page string, finalURL string, err error) {
+	var crawlDelay time.Duration
+	if p.robots != nil {
+		if crawlDelay, err = p.robots.check(ctx, p, url); err != nil {
+			return "", "", err
+		}
+	}
+	err = p.retrying(ctx, url, func() error {
+		if p.robots != nil {
+			if err := p.robots.wait(ctx, hostnameOf(url), crawlDelay); err != nil {
+				return &FetchError{URL: url, Err: err}
+			}
+		}
+		page, finalURL, err = p.fetchOnce(ctx, url)
+		return err
+	})
+	return
+}
+
+// retrying calls attempt until it succeeds, or fails in a way that the
+// RetryPolicy of the Parser does not retry
+func (p *Parser) retrying(ctx context.Context, url string, attempt func() error) error {
+	for n := 0; ; n++ {
+		err := attempt()
+		if err == nil || n >= p.retry.MaxRetries || !retryable(ctx, err) {
+			return err
+		}
+		delay := p.retry.delay(n, err)
+		if delay > p.retry.MaxDelay {
+			p.logger.Tracef("not retrying %s, the server asks to wait %s", url, delay)
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			p.logger.Tracef("not retrying %s, the context ends before %s", url, delay)
+			return err
+		}
+		p.logger.Tracef("retrying %s in %s: %s", url, delay, err)
+		if err := sleep(ctx, delay); err != nil {
+			return &FetchError{URL: url, Err: err}
+		}
+	}
+}
+
+// fetchOnce gets the page at a url, waiting for the rate limit of its host
+func (p *Parser) fetchOnce(ctx context.Context, url string) (page string, finalURL string, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", "", &FetchError{URL: url, Err: err}
+	}
+	for key, values := range p.headers {
+		req.Header[key] = values
+	}
+	if p.limiter != nil {
+		if err := p.limiter.wait(ctx, req.URL.Host); err != nil {
+			return "", "", &FetchError{URL: url, Err: err}
+		}
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", "", &FetchError{URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+	finalURL = resp.Request.URL.String()
+	p.logger.Tracef("fetched %s: %s", finalURL, resp.Status)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", finalURL, &FetchError{URL: url, Err: &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}}
+	}
+	if resp.ContentLength > p.maxBodySize {
+		return "", finalURL, &FetchError{URL: url, Err: fmt.Errorf("%w (%d > %d bytes)", ErrBodyTooLarge, resp.ContentLength, p.maxBodySize)}
+	}
+
+	body, err := p.readBody(resp)
+	if err != nil {
+		return "", finalURL, &FetchError{URL: url, Err: err}
+	}
+	return toUTF8(body, resp.Header.Get("Content-Type")), finalURL, nil
+}
+
+// readBody reads the body of a response, uncompressing it if the client
+// did not, up to the maximum body size
+func (p *Parser) readBody(resp *http.Response) (body []byte, err error) {
+	reader := io.Reader(resp.Body)
+	if !resp.Uncompressed && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	body, err = io.ReadAll(io.LimitReader(reader, p.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > p.maxBodySize {
+		return nil, fmt.Errorf("%w (> %d bytes)", ErrBodyTooLarge, p.maxBodySize)
+	}
+	return body, nil
+}
+
+// toUTF8 converts a page to UTF-8 from the charset of its Content-Type,
+// its byte order mark or its <meta charset>
+func toUTF8(body []byte, contentType string) string {
+	encoding, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		return string(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+	}
+	converted, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return string(body)
+	}
+	return string(converted)
+}
@@ -0,0 +1,148 @@
+package ingredients
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fetchTestPage = "testing/sites/joyfoodsunshine.com/the-most-amazing-chocolate-chip-cookies/index.html"
+
+func TestFetchHeaders(t *testing.T) {
+	b, err := os.ReadFile(fetchTestPage)
+	assert.Nil(t, err)
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	_, err = NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultUserAgent, header.Get("User-Agent"))
+	assert.NotEmpty(t, header.Get("Accept"))
+
+	p := NewParser(WithHeader("User-Agent", "my-app/1.0"), WithHeader("Accept-Language", ""), WithHeader("X-Test", "yes"))
+	_, err = p.NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app/1.0", header.Get("User-Agent"))
+	assert.Empty(t, header.Get("Accept-Language"))
+	assert.Equal(t, "yes", header.Get("X-Test"))
+	// other parsers keep the default headers
+	_, err = NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultUserAgent, header.Get("User-Agent"))
+}
+
+func TestFetchStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "<ul><li>1 cup flour</li><li>2 eggs</li></ul>", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	r, err := NewFromURL(server.URL)
+	assert.Nil(t, r)
+	var fetchErr *FetchError
+	assert.True(t, errors.As(err, &fetchErr))
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusForbidden, statusErr.StatusCode)
+	assert.Equal(t, "could not fetch "+server.URL+": unexpected status 403 Forbidden", err.Error())
+}
+
+func TestFetchMaxBodySize(t *testing.T) {
+	b, err := os.ReadFile(fetchTestPage)
+	assert.Nil(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/chunked" {
+			// no Content-Length
+			w.(http.Flusher).Flush()
+		}
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	p := NewParser(WithMaxBodySize(1000))
+	_, err = p.NewFromURL(server.URL)
+	assert.True(t, errors.Is(err, ErrBodyTooLarge))
+	_, err = p.NewFromURL(server.URL + "/chunked")
+	assert.True(t, errors.Is(err, ErrBodyTooLarge))
+
+	_, err = NewParser(WithMaxBodySize(int64(len(b)))).NewFromURL(server.URL + "/chunked")
+	assert.Nil(t, err)
+}
+
+func TestFetchCharset(t *testing.T) {
+	// "crème fraîche" in ISO-8859-1
+	latin1 := "<html><head>%s</head><body><ul><li>1 cup cr\xe8me fra\xeeche</li><li>1 cup flour</li><li>2 eggs</li></ul></body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/header":
+			w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+			fmt.Fprintf(w, latin1, "")
+		case "/meta":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, latin1, `<meta charset="iso-8859-1">`)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("\xef\xbb\xbf<ul><li>1 cup crème fraîche</li><li>1 cup flour</li><li>2 eggs</li></ul>"))
+		}
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/header", "/meta", "/utf8"} {
+		r, err := NewFromURL(server.URL + path)
+		assert.Nil(t, err, path)
+		assert.Contains(t, r.FileContent, "crème fraîche", path)
+		assert.Equal(t, "egg", r.Lines[len(r.Lines)-1].Ingredient.Name, path)
+	}
+}
+
+func TestFetchGzip(t *testing.T) {
+	b, err := os.ReadFile(fetchTestPage)
+	assert.Nil(t, err)
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(b)
+	gz.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	// uncompressed by the client
+	r, err := NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, r.Ingredients)
+
+	// uncompressed by the parser when gzip is asked for
+	r, err = NewParser(WithHeader("Accept-Encoding", "gzip")).NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, r.Ingredients)
+}
+
+func TestFetchRedirect(t *testing.T) {
+	b, err := os.ReadFile(fetchTestPage)
+	assert.Nil(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/old" {
+			http.Redirect(w, req, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	r, err := NewFromURL(server.URL + "/old")
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/new", r.FileName)
+}
@@ -0,0 +1,229 @@
+package ingredients
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch is an ingredient that was found despite a misspelling,
+// e.g. "parmesean" corrected to "parmesan"
+type FuzzyMatch struct {
+	Original  string `json:"original"`
+	Corrected string `json:"corrected"`
+	// Score is the similarity of the spellings, from 0 to 1
+	Score float64 `json:"score"`
+}
+
+// fuzzyMaxWords is the most words that are compared at once
+const fuzzyMaxWords = 3
+
+// fuzzyMinLength is the shortest text that is compared, since short words
+// are too easily confused
+const fuzzyMinLength = 4
+
+// fuzzyIgnored are the words of nutrition facts, which look like
+// misspelled ingredients, e.g. "carbs" and "carob"
+var fuzzyIgnored = map[string]struct{}{
+	"calories":      {},
+	"carbs":         {},
+	"carbohydrates": {},
+	"cholesterol":   {},
+	"fat":           {},
+	"fiber":         {},
+	"fibre":         {},
+	"kcal":          {},
+	"protein":       {},
+	"sodium":        {},
+}
+
+// bkTree finds the words within an edit distance of a query
+type bkTree struct {
+	root *bkNode
+}
+
+type bkNode struct {
+	word     string
+	children map[int]*bkNode
+}
+
+// newBKTree builds a BK-tree of the words
+func newBKTree(words []string) *bkTree {
+	t := &bkTree{}
+	for _, word := range words {
+		t.insert(word)
+	}
+	return t
+}
+
+func (t *bkTree) insert(word string) {
+	if t.root == nil {
+		t.root = &bkNode{word: word, children: make(map[int]*bkNode)}
+		return
+	}
+	node := t.root
+	for {
+		d := levenshtein(word, node.word)
+		if d == 0 {
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			node.children[d] = &bkNode{word: word, children: make(map[int]*bkNode)}
+			return
+		}
+		node = child
+	}
+}
+
+// search calls found for every word within maxDistance of the query
+func (t *bkTree) search(query string, maxDistance int, found func(word string, distance int)) {
+	if t.root == nil {
+		return
+	}
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d := levenshtein(query, node.word)
+		if d <= maxDistance {
+			found(node.word, d)
+		}
+		for childDistance, child := range node.children {
+			if childDistance >= d-maxDistance && childDistance <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+// levenshtein returns the number of single character insertions,
+// deletions and substitutions that turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// similarity scores two spellings from 0 to 1 by their edit distance
+func similarity(a, b string, distance int) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(distance)/float64(longest)
+}
+
+// lineToken is a word of a line and where it starts and ends
+type lineToken struct {
+	word       string
+	start, end int
+}
+
+// fuzzyIngredient looks for a misspelled ingredient in a line. Without an
+// exact match every run of up to three words is compared to the corpus.
+// With one, only the runs that end with it are, and only an ingredient
+// that ends with it can replace it, so that "choclate chips" becomes
+// "chocolate chips" rather than "chips" while "unsalted butter" stays
+// butter. Typos rarely change the first letter, so corrections keep it,
+// which stops "salted butter" from becoming "unsalted butter".
+func (p *Parser) fuzzyIngredient(lineInfo *LineInfo) (match FuzzyMatch, position int, ok bool) {
+	tokens := p.fuzzyTokens(lineInfo.Line)
+	exactStart, exactEnd, exactSuffix := -1, -1, ""
+	if len(lineInfo.IngredientsInString) > 0 {
+		exact := lineInfo.IngredientsInString[0]
+		exactStart = exact.Position + 1
+		exactEnd = exactStart + len([]rune(exact.Word))
+		exactSuffix = " " + exact.Word
+	}
+
+	bestLength := 0
+	for i := range tokens {
+		for j := i; j < len(tokens) && j < i+fuzzyMaxWords; j++ {
+			if j > i && tokens[j].start != tokens[j-1].end+1 {
+				// the words are not next to each other
+				break
+			}
+			start, end := tokens[i].start, tokens[j].end
+			if exactStart >= 0 && (start >= exactStart || end != exactEnd) {
+				continue
+			}
+			words := make([]string, 0, j-i+1)
+			for _, token := range tokens[i : j+1] {
+				words = append(words, token.word)
+			}
+			query := strings.Join(words, " ")
+			length := len([]rune(query))
+			if length < fuzzyMinLength {
+				continue
+			}
+			maxDistance := int((1 - p.fuzzyThreshold) * float64(length) / p.fuzzyThreshold)
+			p.fuzzyTree.search(query, maxDistance, func(word string, distance int) {
+				score := similarity(query, word, distance)
+				if distance == 0 || score < p.fuzzyThreshold || []rune(word)[0] != []rune(query)[0] {
+					return
+				}
+				if exactSuffix != "" && !p.fuzzyExtends(query, word, exactSuffix) {
+					return
+				}
+				if score > match.Score || (score == match.Score && length > bestLength) {
+					match = FuzzyMatch{Original: query, Corrected: word, Score: score}
+					position = start - 1
+					bestLength = length
+					ok = true
+				}
+			})
+		}
+	}
+	return
+}
+
+// fuzzyExtends returns whether a correction of the words before an exact
+// match keeps the match at its end and is a correction of those words on
+// their own, so that "ground coriander" does not become "green coriander"
+func (p *Parser) fuzzyExtends(query, word, exactSuffix string) bool {
+	if !strings.HasSuffix(word, exactSuffix) {
+		return false
+	}
+	before := strings.TrimSuffix(query, exactSuffix)
+	corrected := strings.TrimSuffix(word, exactSuffix)
+	return similarity(before, corrected, levenshtein(before, corrected)) >= p.fuzzyThreshold
+}
+
+// fuzzyTokens returns the words of a line that could be part of an
+// ingredient, leaving out numbers, measures and nutrition facts
+func (p *Parser) fuzzyTokens(line string) (tokens []lineToken) {
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == ' ' {
+			continue
+		}
+		j := i
+		for j < len(runes) && runes[j] != ' ' {
+			j++
+		}
+		word := string(runes[i:j])
+		_, isMeasure := p.corpus.Measures[word]
+		_, isNumber := wordNumbers[word]
+		_, isIgnored := fuzzyIgnored[word]
+		if !isMeasure && !isNumber && !isIgnored && !strings.ContainsFunc(word, unicode.IsNumber) {
+			tokens = append(tokens, lineToken{word: word, start: i, end: j})
+		}
+		i = j
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package ingredients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("flour", "flour"))
+	assert.Equal(t, 1, levenshtein("parmesean", "parmesan"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 5, levenshtein("", "sugar"))
+	assert.Equal(t, 1, levenshtein("jalapeño", "jalapeno"))
+}
+
+func TestBKTree(t *testing.T) {
+	tree := newBKTree([]string{"sugar", "sumac", "flour", "butter", "cutter", "batter"})
+	found := make(map[string]int)
+	tree.search("buter", 1, func(word string, distance int) {
+		found[word] = distance
+	})
+	assert.Equal(t, map[string]int{"butter": 1}, found)
+
+	found = make(map[string]int)
+	tree.search("butter", 1, func(word string, distance int) {
+		found[word] = distance
+	})
+	assert.Equal(t, map[string]int{"butter": 0, "cutter": 1, "batter": 1}, found)
+}
+
+func TestFuzzyMatching(t *testing.T) {
+	lines := []string{"2 cups choclate chips", "1/2 cup parmesean", "1 jalepeno, diced", "1 cup salted butter", "1 tbsp shuger"}
+	p := NewParser(WithFuzzyMatching(0.8))
+	r := &Recipe{FileName: "lines", parser: p}
+	_, r.Lines = p.scoreLines(lines)
+	assert.Nil(t, r.parseRecipe(false))
+	assert.Equal(t, 4, len(r.Lines))
+
+	assert.Equal(t, "chocolate chip", r.Lines[0].Ingredient.Name)
+	assert.Equal(t, &FuzzyMatch{Original: "choclate chips", Corrected: "chocolate chips", Score: 1 - 1.0/15}, r.Lines[0].Fuzzy)
+	assert.Equal(t, 2.0, r.Lines[0].Ingredient.Measure.Amount)
+	assert.Equal(t, "parmesan", r.Lines[1].Ingredient.Name)
+	assert.Equal(t, "parmesean", r.Lines[1].Fuzzy.Original)
+	assert.Equal(t, "jalapeno", r.Lines[2].Ingredient.Name)
+	// correctly spelled ingredients are left alone
+	assert.Equal(t, "butter", r.Lines[3].Ingredient.Name)
+	assert.Nil(t, r.Lines[3].Fuzzy)
+
+	// words next to a correctly spelled ingredient are not corrected into
+	// another ingredient, and nutrition facts are not ingredients
+	for line, name := range map[string]string{
+		"2 tablespoons unsalted butter":  "butter",
+		"3 garlic cloves, minced":        "garlic",
+		"½ teaspoon ground coriander":    "coriander",
+		"melted butter or vegetable oil": "butter",
+		"Carbs 18 g":                     "",
+	} {
+		_, lineInfo := p.scoreLine(line)
+		lineInfo.getIngredient(p)
+		assert.Equal(t, name, lineInfo.Ingredient.Name, line)
+		assert.Nil(t, lineInfo.Fuzzy, line)
+	}
+
+	// without fuzzy matching the misspelled ingredients are dropped
+	ingredientList, err := ParseTextIngredients("1/2 cup parmesean\n1 jalepeno, diced\n1 cup butter")
+	assert.Nil(t, err)
+	assert.Equal(t, "1 cup butter\n", ingredientList.String())
+
+	// a stricter threshold allows fewer typos
+	ingredientList, err = NewParser(WithFuzzyMatching(0.95)).ParseTextIngredients("1/2 cup parmesean\n1 cup butter")
+	assert.Nil(t, err)
+	assert.Equal(t, "1 cup butter\n", ingredientList.String())
+}
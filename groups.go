@@ -0,0 +1,292 @@
+package ingredients
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// IngredientGroup is a titled section of a recipe's ingredients,
+// e.g. "For the frosting"
+type IngredientGroup struct {
+	Title       string       `json:"title"`
+	Ingredients []Ingredient `json:"ingredients"`
+}
+
+// Consolidation determines which lines are merged when the same
+// ingredient appears more than once in a recipe
+type Consolidation int
+
+const (
+	// ConsolidateAcrossGroups merges every line with the same ingredient
+	ConsolidateAcrossGroups Consolidation = iota
+	// ConsolidateWithinGroups only merges lines from the same ingredient group
+	ConsolidateWithinGroups
+	// ConsolidateToParents also merges every line into a more general
+	// ingredient of the recipe, e.g. "aged cheddar" into "cheese"
+	ConsolidateToParents
+)
+
+// Consolidate merges repeated ingredients in the recipe lines
+func (r *Recipe) Consolidate(mode Consolidation) (ingredients []Ingredient) {
+	switch mode {
+	case ConsolidateAcrossGroups:
+		return consolidate(r.Lines)
+	case ConsolidateToParents:
+		return consolidate(r.getParser().toParents(r.Lines))
+	}
+	for _, group := range r.IngredientGroups() {
+		ingredients = append(ingredients, group.Ingredients...)
+	}
+	return
+}
+
+// IngredientGroups returns the ingredients of each group in the recipe,
+// consolidated within the group. A recipe without sections has one
+// group with an empty title.
+func (r *Recipe) IngredientGroups() (groups []IngredientGroup) {
+	titles := []string{}
+	linesInGroup := make(map[string][]LineInfo)
+	for _, line := range r.Lines {
+		title := line.Ingredient.Group
+		if _, ok := linesInGroup[title]; !ok {
+			titles = append(titles, title)
+		}
+		linesInGroup[title] = append(linesInGroup[title], line)
+	}
+	groups = make([]IngredientGroup, len(titles))
+	for i, title := range titles {
+		groups[i] = IngredientGroup{
+			Title:       title,
+			Ingredients: consolidate(linesInGroup[title]),
+		}
+	}
+	return
+}
+
+// consolidate merges lines with the same ingredient ID and alternatives,
+// adding amounts when the measures match and always adding the cups
+func consolidate(lines []LineInfo) []Ingredient {
+	ingredients := make(map[string]Ingredient)
+	ingredientList := []string{}
+	for _, line := range lines {
+		key := consolidationKey(line.Ingredient)
+		if existing, ok := ingredients[key]; ok {
+			merged := Ingredient{
+				Name:        existing.Name,
+				ID:          existing.ID,
+				Category:    existing.Category,
+				Comment:     existing.Comment,
+				Group:       existing.Group,
+				Preparation: mergePreparations(existing.Preparation, line.Ingredient.Preparation),
+				Size:        existing.Size,
+				// only optional or to taste if every line is
+				Optional:     existing.Optional && line.Ingredient.Optional,
+				ToTaste:      existing.ToTaste && line.Ingredient.ToTaste,
+				Divided:      existing.Divided || line.Ingredient.Divided,
+				Alternatives: addAlternatives(existing.Alternatives, line.Ingredient.Alternatives),
+				Measure: Measure{
+					Name:         existing.Measure.Name,
+					Amount:       existing.Measure.Amount,
+					Cups:         existing.Measure.Cups + line.Ingredient.Measure.Cups,
+					Weight:       existing.Measure.Weight + line.Ingredient.Measure.Weight,
+					WeightSource: leastPrecise(existing.Measure.WeightSource, line.Ingredient.Measure.WeightSource),
+				},
+			}
+			if existing.Measure.Name == line.Ingredient.Measure.Name {
+				merged.Measure.Amount += line.Ingredient.Measure.Amount
+				merged.Measure.Approximate = existing.Measure.Approximate || line.Ingredient.Measure.Approximate
+				if existing.Measure.IsRange() || line.Ingredient.Measure.IsRange() {
+					lo1, hi1 := existing.Measure.bounds()
+					lo2, hi2 := line.Ingredient.Measure.bounds()
+					merged.Measure.Min, merged.Measure.Max = lo1+lo2, hi1+hi2
+				}
+			} else {
+				merged.Measure.Min = existing.Measure.Min
+				merged.Measure.Max = existing.Measure.Max
+				merged.Measure.Approximate = existing.Measure.Approximate
+			}
+			if merged.Measure.WeightSource == "" {
+				merged.Measure.Weight = 0
+			}
+			if existing.Group != line.Ingredient.Group {
+				merged.Group = ""
+			}
+			ingredients[key] = merged
+		} else {
+			ingredientList = append(ingredientList, key)
+			ingredients[key] = Ingredient{
+				Name:        line.Ingredient.Name,
+				ID:          line.Ingredient.ID,
+				Category:    line.Ingredient.Category,
+				Comment:     line.Ingredient.Comment,
+				Group:       line.Ingredient.Group,
+				Preparation: line.Ingredient.Preparation,
+				Size:        line.Ingredient.Size,
+				Optional:    line.Ingredient.Optional,
+				ToTaste:     line.Ingredient.ToTaste,
+				Divided:     line.Ingredient.Divided,
+				// the alternatives are scaled and converted with the ingredient
+				Alternatives: slices.Clone(line.Ingredient.Alternatives),
+				Measure: Measure{
+					Name:         line.Ingredient.Measure.Name,
+					Amount:       line.Ingredient.Measure.Amount,
+					Cups:         line.Ingredient.Measure.Cups,
+					Weight:       line.Ingredient.Measure.Weight,
+					WeightSource: line.Ingredient.Measure.WeightSource,
+					Min:          line.Ingredient.Measure.Min,
+					Max:          line.Ingredient.Measure.Max,
+					Approximate:  line.Ingredient.Measure.Approximate,
+					Parts:        line.Ingredient.Measure.Parts,
+					Package:      line.Ingredient.Measure.Package,
+				},
+			}
+		}
+	}
+	consolidated := make([]Ingredient, len(ingredientList))
+	for i, ing := range ingredientList {
+		consolidated[i] = ingredients[ing]
+	}
+	return consolidated
+}
+
+// consolidationKey identifies the lines that consolidate merges. Lines
+// with different alternatives are kept apart, since an alternative is
+// only for the amount of its own line.
+func consolidationKey(ing Ingredient) string {
+	key := ing.ID
+	if key == "" {
+		key = ing.Name
+	}
+	for _, alternative := range ing.Alternatives {
+		id := alternative.ID
+		if id == "" {
+			id = alternative.Name
+		}
+		key += " or " + id
+	}
+	return key
+}
+
+// addAlternatives adds the measures of the same alternatives of two lines
+func addAlternatives(existing, other []Ingredient) []Ingredient {
+	added := slices.Clone(existing)
+	for i := range added {
+		if added[i].Measure.Name == other[i].Measure.Name {
+			added[i].Measure.Amount += other[i].Measure.Amount
+		}
+		added[i].Measure.Cups += other[i].Measure.Cups
+		added[i].Measure.Weight += other[i].Measure.Weight
+	}
+	return added
+}
+
+// assignGroups removes the header lines (e.g. "For the frosting:") from
+// a list of lines and sets the group of every line after a header
+func assignGroups(lineInfos []LineInfo, title string) []LineInfo {
+	grouped := lineInfos[:0]
+	for _, lineInfo := range lineInfos {
+		if header, ok := groupHeader(lineInfo.LineOriginal); ok {
+			title = header
+			continue
+		}
+		lineInfo.Ingredient.Group = title
+		grouped = append(grouped, lineInfo)
+	}
+	return grouped
+}
+
+// sectionLines flattens a schema.org HowToSection of ingredients into a
+// header line followed by the ingredient lines
+func sectionLines(section map[string]interface{}) (lines []string) {
+	name, _ := section["name"].(string)
+	lines = append(lines, strings.TrimSpace(name)+":")
+	var f func(v interface{})
+	f = func(v interface{}) {
+		switch val := v.(type) {
+		case string:
+			lines = append(lines, val)
+		case []interface{}:
+			for _, item := range val {
+				f(item)
+			}
+		case map[string]interface{}:
+			if text, ok := val["text"].(string); ok {
+				lines = append(lines, text)
+			} else if name, ok := val["name"].(string); ok {
+				lines = append(lines, name)
+			}
+		}
+	}
+	f(section["itemListElement"])
+	return
+}
+
+// groupHeader determines whether a line is a section header like
+// "For the frosting:" and returns its title. Generic headers like
+// "Ingredients:" are headers with an empty title.
+func groupHeader(line string) (title string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasSuffix(line, ":") || len(line) > 60 {
+		return
+	}
+	title = cleanGroupTitle(line)
+	if len(GetNumbersInString(SanitizeLine(title))) > 0 && len(GetMeasuresInString(SanitizeLine(title))) > 0 {
+		// "2 cups flour:" is an ingredient, not a header
+		return "", false
+	}
+	return title, true
+}
+
+// groupTitleBefore returns the title of the heading immediately
+// before the node, if there is one
+func groupTitleBefore(n *html.Node) (title string) {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.TextNode && strings.TrimSpace(s.Data) == "" {
+			continue
+		}
+		if s.Type != html.ElementNode && s.Type != html.TextNode {
+			continue
+		}
+		text := strings.TrimSpace(s.Data)
+		if s.Type == html.ElementNode {
+			text = nodeText(s)
+		}
+		switch s.DataAtom {
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			if len(text) <= 60 {
+				title = cleanGroupTitle(text)
+			}
+		default:
+			title, _ = groupHeader(text)
+		}
+		return
+	}
+	return
+}
+
+// cleanGroupTitle trims a heading and drops generic titles
+func cleanGroupTitle(s string) string {
+	s = strings.TrimSpace(strings.Trim(strings.TrimSpace(s), "*-#:"))
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, "ingredient") || strings.HasPrefix(lower, "you will need") || strings.HasPrefix(lower, "you'll need") {
+		return ""
+	}
+	return s
+}
+
+// mergePreparations returns the preparations of two lines without repeats
+func mergePreparations(a, b []string) []string {
+	merged := append([]string{}, a...)
+	for _, preparation := range b {
+		if !slices.Contains(merged, preparation) {
+			merged = append(merged, preparation)
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
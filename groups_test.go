@@ -0,0 +1,91 @@
+package ingredients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupsText(t *testing.T) {
+	ingredientList, err := ParseTextIngredients(`Ingredients:
+2 cups flour
+1 cup butter
+For the frosting:
+1/2 cup butter
+2 cups powdered sugar`)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(ingredientList.Ingredients))
+	assert.Equal(t, "", ingredientList.Ingredients[1].Group)
+	assert.Equal(t, "For the frosting", ingredientList.Ingredients[2].Group)
+	assert.Equal(t, `2 cups flour
+1 cup butter
+
+For the frosting:
+1/2 cup butter
+2 cups powdered sugar`, strings.TrimSpace(ingredientList.String()))
+}
+
+func TestGroupsDOM(t *testing.T) {
+	htmlString := `<html><body>
+	<div class="wprm-recipe-ingredient-group">
+		<h4 class="wprm-recipe-group-name">Cake</h4>
+		<ul>
+			<li>2 cups flour</li>
+			<li>1 cup sugar</li>
+			<li>1/2 cup butter</li>
+		</ul>
+	</div>
+	<div class="wprm-recipe-ingredient-group">
+		<h4 class="wprm-recipe-group-name">Frosting</h4>
+		<ul>
+			<li>1/4 cup butter</li>
+			<li>2 cups powdered sugar</li>
+		</ul>
+	</div>
+	</body></html>`
+	r, err := NewFromHTML("test", htmlString)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(r.Groups))
+	assert.Equal(t, "Cake", r.Groups[0].Title)
+	assert.Equal(t, 3, len(r.Groups[0].Ingredients))
+	assert.Equal(t, "Frosting", r.Groups[1].Title)
+	assert.Equal(t, 2, len(r.Groups[1].Ingredients))
+
+	// by default butter is consolidated across the groups
+	assert.Equal(t, 4, len(r.Ingredients))
+	assert.Equal(t, 0.75, r.Ingredients[2].Measure.Amount)
+	assert.Equal(t, "", r.Ingredients[2].Group)
+
+	// but can be kept separate
+	ingredients := r.Consolidate(ConsolidateWithinGroups)
+	assert.Equal(t, 5, len(ingredients))
+	assert.Equal(t, "butter", ingredients[3].Name)
+	assert.Equal(t, 0.25, ingredients[3].Measure.Amount)
+	assert.Equal(t, "Frosting", ingredients[3].Group)
+}
+
+func TestGroupsSchemaOrg(t *testing.T) {
+	htmlString := `<html><head><script type="application/ld+json">
+{
+	"@context": "https://schema.org",
+	"@type": "Recipe",
+	"name": "Pasta with sauce",
+	"recipeIngredient": [
+		{"@type": "HowToSection", "name": "Pasta", "itemListElement": ["1 pound spaghetti", "1 tablespoon salt"]},
+		{"@type": "HowToSection", "name": "Sauce", "itemListElement": [
+			{"@type": "HowToSupply", "name": "2 tablespoons olive oil"},
+			{"@type": "HowToSupply", "name": "1 small onion"},
+			{"@type": "HowToSupply", "name": "1 teaspoon salt"}
+		]}
+	]
+}
+</script></head><body></body></html>`
+	r, err := NewFromHTML("test", htmlString)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(r.Lines))
+	assert.Equal(t, []string{"Pasta", "Sauce"}, []string{r.Groups[0].Title, r.Groups[1].Title})
+	assert.Equal(t, "onion", r.Groups[1].Ingredients[1].Name)
+	assert.Equal(t, 4, len(r.Ingredients))
+	assert.Equal(t, 5, len(r.Consolidate(ConsolidateWithinGroups)))
+}
@@ -0,0 +1,54 @@
+package ingredients
+
+// canonical returns the ID of an ingredient, which is the canonical name
+// of its synonyms or the name itself
+func (p *Parser) canonical(name string) string {
+	if id, ok := p.corpus.Synonyms[name]; ok {
+		return id
+	}
+	return name
+}
+
+// lineage returns the ID of an ingredient followed by the IDs of its
+// parents, from the most to the least specific
+func (p *Parser) lineage(name string) (ids []string) {
+	seen := make(map[string]bool)
+	for id := p.canonical(name); id != "" && !seen[id]; id = p.canonical(p.corpus.Parents[id]) {
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return
+}
+
+// density returns the grams per cup of an ingredient by its ID, so that
+// its synonyms weigh the same, and then up its parents
+func (p *Parser) density(ingredient string) (density float64, ok bool) {
+	for _, id := range p.lineage(ingredient) {
+		if density, ok = p.densitiesByID[id]; ok {
+			return
+		}
+	}
+	density, ok = p.corpus.Densities[ingredient]
+	return
+}
+
+// toParents renames the lines whose ingredient has a parent elsewhere in
+// the lines to the most general such parent, so that they are consolidated
+func (p *Parser) toParents(lines []LineInfo) []LineInfo {
+	present := make(map[string]Ingredient)
+	for _, line := range lines {
+		if _, ok := present[line.Ingredient.ID]; !ok && line.Ingredient.ID != "" {
+			present[line.Ingredient.ID] = line.Ingredient
+		}
+	}
+	renamed := make([]LineInfo, len(lines))
+	for i, line := range lines {
+		for _, id := range p.lineage(line.Ingredient.ID) {
+			if parent, ok := present[id]; ok {
+				line.Ingredient.Name, line.Ingredient.ID, line.Ingredient.Category = parent.Name, parent.ID, parent.Category
+			}
+		}
+		renamed[i] = line
+	}
+	return renamed
+}
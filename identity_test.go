@@ -0,0 +1,78 @@
+package ingredients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentitySynonyms(t *testing.T) {
+	r := parseLines(t, "1 courgette\n2 zucchinis\n1 tsp bicarbonate of soda")
+	assert.Equal(t, 2, len(r.Ingredients))
+	assert.Equal(t, "courgette", r.Ingredients[0].Name)
+	assert.Equal(t, "zucchini", r.Ingredients[0].ID)
+	assert.Equal(t, 3.0, r.Ingredients[0].Measure.Amount)
+	assert.Equal(t, "bicarbonate of soda", r.Ingredients[1].Name)
+	assert.Equal(t, "baking soda", r.Ingredients[1].ID)
+	assert.Equal(t, "flour", parseLines(t, "1 cup flour\n2 eggs").Ingredients[0].ID)
+}
+
+func TestIdentityLineage(t *testing.T) {
+	assert.Equal(t, []string{"aged cheddar", "cheddar", "cheese", "dairy"}, defaultParser.lineage("aged cheddar"))
+	assert.Equal(t, []string{"cheddar", "cheese", "dairy"}, defaultParser.lineage("cheddar cheese"))
+	assert.Equal(t, []string{"saffron"}, defaultParser.lineage("saffron"))
+
+	// cycles end the lineage
+	p := NewParser(WithCorpusAdditions(Corpus{Parents: map[string]string{"chicken": "egg", "egg": "chicken"}}))
+	assert.Equal(t, []string{"egg", "chicken"}, p.lineage("egg"))
+}
+
+func TestIdentityDensity(t *testing.T) {
+	// aged cheddar has no density of its own, cheddar cheese does
+	density, ok := defaultParser.density("aged cheddar")
+	assert.True(t, ok)
+	assert.Equal(t, densities["cheddar cheese"], density)
+
+	r := parseLines(t, "1 cup aged cheddar\n2 eggs")
+	assert.Equal(t, densities["cheddar cheese"], r.Ingredients[0].Measure.Weight)
+	assert.Equal(t, WeightDensity, r.Ingredients[0].Measure.WeightSource)
+
+	p := NewParser(WithCorpusAdditions(Corpus{
+		Ingredients: []string{"wattleseed"},
+		Parents:     map[string]string{"wattleseed": "flour"},
+	}))
+	density, ok = p.density("wattleseed")
+	assert.True(t, ok)
+	assert.Equal(t, densities["flour"], density)
+}
+
+func TestIdentitySynonymsWeighTheSame(t *testing.T) {
+	r := parseLines(t, "1 cup spring onion\n1 cup scallion\n1 cup green onion\n2 spring onions\n2 scallions")
+	for _, line := range r.Lines {
+		assert.Equal(t, "green onion", line.Ingredient.ID, line.LineOriginal)
+		assert.Equal(t, CategoryVegetable, line.Ingredient.Category, line.LineOriginal)
+		assert.Equal(t, WeightDensity, line.Ingredient.Measure.WeightSource, line.LineOriginal)
+	}
+	for _, line := range r.Lines[1:3] {
+		assert.Equal(t, r.Lines[0].Ingredient.Measure.Weight, line.Ingredient.Measure.Weight, line.LineOriginal)
+	}
+	assert.Equal(t, r.Lines[3].Ingredient.Measure.Weight, r.Lines[4].Ingredient.Measure.Weight)
+	assert.Equal(t, r.Lines[3].Ingredient.Measure.Cups, r.Lines[4].Ingredient.Measure.Cups)
+}
+
+func TestConsolidateToParents(t *testing.T) {
+	r := parseLines(t, "1 cup cheddar cheese\n1/2 cup aged cheddar\n1 cup milk\n1 cup mozzarella")
+	assert.Equal(t, 4, len(r.Ingredients))
+
+	ingredients := r.Consolidate(ConsolidateToParents)
+	assert.Equal(t, 3, len(ingredients))
+	assert.Equal(t, "cheddar cheese", ingredients[0].Name)
+	assert.Equal(t, 1.5, ingredients[0].Measure.Amount)
+
+	// with cheese in the recipe, every cheese is merged into it
+	r = parseLines(t, "1 cup cheddar cheese\n1/2 cup aged cheddar\n1 cup mozzarella\n1 cup cheese")
+	ingredients = r.Consolidate(ConsolidateToParents)
+	assert.Equal(t, 1, len(ingredients))
+	assert.Equal(t, "cheese", ingredients[0].Name)
+	assert.Equal(t, 3.5, ingredients[0].Measure.Amount)
+}
@@ -7,36 +7,43 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"math"
-	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/astappiev/microdata"
 	json "github.com/goccy/go-json"
-	"github.com/jinzhu/inflection"
-	log "github.com/schollz/logger"
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 )
 
-func init() {
-	inflection.AddSingular("(clove)(s)?$", "${1}")
-	inflection.AddSingular("(potato)(es)?$", "${1}")
-	inflection.AddSingular("(tomato)(es)?$", "${1}")
-	inflection.AddUncountable("molasses")
-	inflection.AddUncountable("bacon")
-}
-
 // Recipe contains the info for the file and the lines
 type Recipe struct {
 	FileName    string       `json:"filename"`
 	FileContent string       `json:"file_content"`
 	Lines       []LineInfo   `json:"lines"`
 	Ingredients []Ingredient `json:"ingredients"`
+	Directions  []Step       `json:"directions,omitempty"`
+	Metadata    Metadata     `json:"metadata"`
+	// Groups has the ingredients of each section when the recipe has sections
+	Groups []IngredientGroup `json:"groups,omitempty"`
+	// Rejected are the candidate lines that were not ingredients, with the
+	// Reasons why
+	Rejected []LineInfo `json:"rejected,omitempty"`
+
+	// parser is the Parser that made the recipe, nil for the default one
+	parser *Parser
+}
+
+// getParser returns the Parser that made the recipe
+func (r *Recipe) getParser() *Parser {
+	if r.parser == nil {
+		return defaultParser
+	}
+	return r.parser
 }
 
 // LineInfo has all the information for the parsing of a given line
@@ -47,38 +54,129 @@ type LineInfo struct {
 	AmountInString      []WordPosition `json:",omitempty"`
 	MeasureInString     []WordPosition `json:",omitempty"`
 	Ingredient          Ingredient     `json:",omitempty"`
-	Source              string         `json:",omitempty"` // "schema.org" or "dom"
+	Source              string         `json:",omitempty"` // "schema.org", "dom" or the name of an Extractor
+	// Confidence is how much the line looks like an ingredient, from 0 to
+	// 1. Reasons say what lowered it and why a line was rejected.
+	Confidence float64  `json:",omitempty"`
+	Reasons    []string `json:",omitempty"`
+	// Fuzzy has the original and corrected spelling of a misspelled ingredient
+	Fuzzy *FuzzyMatch `json:",omitempty"`
 }
 
 // Ingredient is the basic struct for ingredients
 type Ingredient struct {
-	Name    string  `json:"name,omitempty"`
-	Comment string  `json:"comment,omitempty"`
-	Measure Measure `json:"measure,omitempty"`
-	Line    string  `json:"line,omitempty"`
+	Name string `json:"name,omitempty"`
+	// ID is the canonical name of the ingredient, shared by its synonyms
+	ID       string   `json:"id,omitempty"`
+	Category Category `json:"category,omitempty"`
+	Comment  string   `json:"comment,omitempty"`
+	Measure  Measure  `json:"measure,omitempty"`
+	Line     string   `json:"line,omitempty"`
+	Group    string   `json:"group,omitempty"`
+	// Preparation is how the ingredient is prepared, e.g. "finely chopped"
+	// or "melted"
+	Preparation []string `json:"preparation,omitempty"`
+	// Size describes the ingredient, e.g. "large" for eggs
+	Size     string `json:"size,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	ToTaste  bool   `json:"to_taste,omitempty"`
+	// Divided means the ingredient is used in more than one step
+	Divided bool `json:"divided,omitempty"`
+	// Alternatives can be used instead of the ingredient, e.g. the honey of
+	// "1 cup sugar or honey"
+	Alternatives []Ingredient `json:"alternatives,omitempty"`
 }
 
-// Measure includes the amount, name and the cups for conversions
+// Measure includes the amount, name and the cups for conversions.
+// Ranges like "2-3" keep their ends in Min and Max, and Amount is Max.
+// Weight is in grams and WeightSource says how it was determined.
 type Measure struct {
-	Amount float64 `json:"amount"`
-	Name   string  `json:"name"`
-	Cups   float64 `json:"cups"`
-	Weight float64 `json:"weight,omitempty"`
+	Amount       float64      `json:"amount"`
+	Name         string       `json:"name"`
+	Cups         float64      `json:"cups"`
+	Weight       float64      `json:"weight,omitempty"`
+	WeightSource WeightSource `json:"weight_source,omitempty"`
+	Min          float64      `json:"min,omitempty"`
+	Max          float64      `json:"max,omitempty"`
+	Approximate  bool         `json:"approximate,omitempty"`
+	// Parts are the original quantities of a compound measure like
+	// "1 cup plus 2 tablespoons", whose total is in Amount and Cups
+	Parts []Measure `json:"parts,omitempty"`
+	// Joiner is the word between a part and the one before it, like
+	// "plus" or "+", empty if they are only next to each other
+	Joiner string `json:"joiner,omitempty"`
+	// Package is set for lines like "1 (12-ounce) bag", whose total is
+	// in Amount and Name
+	Package *Package `json:"package,omitempty"`
+}
+
+// IsRange reports whether the measure is a range like "2-3"
+func (m Measure) IsRange() bool {
+	return m.Max > m.Min && m.Min > 0
+}
+
+// AmountString renders the amount for display, e.g. "1 1/2", "2–3" or "about 2"
+func (m Measure) AmountString() (s string) {
+	if m.IsRange() {
+		s = AmountToString(m.Min, m.Max)
+	} else {
+		s = AmountToString(m.Amount)
+	}
+	if m.Approximate {
+		s = "about " + s
+	}
+	return
+}
+
+// bounds returns the lowest and highest amount of the measure
+func (m Measure) bounds() (lo, hi float64) {
+	if m.IsRange() {
+		return m.Min, m.Max
+	}
+	return m.Amount, m.Amount
 }
 
 // IngredientList is a list of ingredients
 type IngredientList struct {
 	Ingredients []Ingredient `json:"ingredients"`
+
+	// parser is the Parser of the recipe, nil for the default one
+	parser *Parser
+}
+
+// getParser returns the Parser of the recipe of the list
+func (il IngredientList) getParser() *Parser {
+	if il.parser == nil {
+		return defaultParser
+	}
+	return il.parser
 }
 
 func (il IngredientList) String() string {
 	s := ""
+	group := ""
 	for _, ing := range il.Ingredients {
+		if ing.Group != group && ing.Group != "" {
+			if s != "" {
+				s += "\n"
+			}
+			s += ing.Group + ":\n"
+		}
+		group = ing.Group
 		name := ing.Name
 		if ing.Measure.Amount > 1 && ing.Measure.Name == "whole" {
-			name = inflection.Plural(name)
+			name = il.getParser().plural(name)
+		}
+		if ing.Measure.Package != nil {
+			s += fmt.Sprintf("%s %s", ing.Measure.Package, name)
+		} else if len(ing.Measure.Parts) > 1 {
+			s += fmt.Sprintf("%s %s", ing.Measure.PartsString(), name)
+		} else {
+			s += fmt.Sprintf("%s %s %s", ing.Measure.AmountString(), ing.Measure.Name, name)
+		}
+		for _, alternative := range ing.Alternatives {
+			s += " or " + alternative.Name
 		}
-		s += fmt.Sprintf("%s %s %s", AmountToString(ing.Measure.Amount), ing.Measure.Name, name)
 		if ing.Comment != "" {
 			s += " (" + ing.Comment + ")"
 		}
@@ -111,7 +209,13 @@ func Load(fname string) (r *Recipe, err error) {
 // ParseTextIngredients parses a list of ingredients and
 // returns an ingredient list back
 func ParseTextIngredients(text string) (ingredientList IngredientList, err error) {
-	r := &Recipe{FileName: "lines"}
+	return defaultParser.ParseTextIngredients(text)
+}
+
+// ParseTextIngredients parses a list of ingredients and
+// returns an ingredient list back
+func (p *Parser) ParseTextIngredients(text string) (ingredientList IngredientList, err error) {
+	r := &Recipe{FileName: "lines", parser: p}
 	r.FileContent = text
 	lines := strings.Split(text, "\n")
 	i := 0
@@ -124,7 +228,8 @@ func ParseTextIngredients(text string) (ingredientList IngredientList, err error
 		goodLines[i] = line
 		i++
 	}
-	_, r.Lines = scoreLines(goodLines)
+	_, r.Lines = p.scoreLines(goodLines)
+	r.Lines = assignGroups(r.Lines, "")
 	err = r.parseRecipe(false) // Don't enforce minimum for text parsing
 	if err != nil {
 		return
@@ -136,8 +241,16 @@ func ParseTextIngredients(text string) (ingredientList IngredientList, err error
 
 // NewFromFile generates a new parser from a HTML file
 func NewFromFile(fname string) (r *Recipe, err error) {
-	r = &Recipe{FileName: fname}
+	return defaultParser.NewFromFile(fname)
+}
+
+// NewFromFile parses a recipe from a HTML file
+func (p *Parser) NewFromFile(fname string) (r *Recipe, err error) {
 	b, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, fmt.Errorf("could not read recipe: %w", err)
+	}
+	r = &Recipe{FileName: fname, parser: p}
 	r.FileContent = string(b)
 	err = r.parseHTML()
 	return
@@ -145,7 +258,12 @@ func NewFromFile(fname string) (r *Recipe, err error) {
 
 // NewFromString generates a new parser from a HTML string
 func NewFromString(htmlString string) (r *Recipe, err error) {
-	r = &Recipe{FileName: "string"}
+	return defaultParser.NewFromString(htmlString)
+}
+
+// NewFromString parses a recipe from a HTML string
+func (p *Parser) NewFromString(htmlString string) (r *Recipe, err error) {
+	r = &Recipe{FileName: "string", parser: p}
 	r.FileContent = htmlString
 	err = r.parseHTML()
 	return
@@ -154,37 +272,40 @@ func NewFromString(htmlString string) (r *Recipe, err error) {
 // NewFromURL generates a new parser from a url with a default 10-second timeout.
 // For custom timeout or cancellation support, use NewFromURLWithContext.
 func NewFromURL(url string) (r *Recipe, err error) {
+	return defaultParser.NewFromURL(url)
+}
+
+// NewFromURL parses a recipe from a url with a default 10-second timeout
+func (p *Parser) NewFromURL(url string) (r *Recipe, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	return NewFromURLWithContext(ctx, url)
+	return p.NewFromURLWithContext(ctx, url)
 }
 
 // NewFromURLWithContext generates a new parser from a url with context support.
 // This allows for custom timeouts and request cancellation.
 func NewFromURLWithContext(ctx context.Context, url string) (r *Recipe, err error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return defaultParser.NewFromURLWithContext(ctx, url)
+}
 
-	html, err := io.ReadAll(resp.Body)
+// NewFromURLWithContext parses a recipe from a url with the Parser's HTTP
+// client. The FileName of the recipe is the url after any redirects.
+func (p *Parser) NewFromURLWithContext(ctx context.Context, url string) (r *Recipe, err error) {
+	page, finalURL, err := p.fetch(ctx, url)
 	if err != nil {
 		return nil, err
 	}
-
-	return NewFromHTML(url, string(html))
+	return p.NewFromHTML(finalURL, page)
 }
 
 // NewFromHTML generates a new parser from a HTML text
 func NewFromHTML(name, htmlstring string) (r *Recipe, err error) {
-	r = &Recipe{FileName: name}
+	return defaultParser.NewFromHTML(name, htmlstring)
+}
+
+// NewFromHTML parses a recipe from a HTML text
+func (p *Parser) NewFromHTML(name, htmlstring string) (r *Recipe, err error) {
+	r = &Recipe{FileName: name, parser: p}
 	r.FileContent = htmlstring
 	err = r.parseHTML()
 	return
@@ -205,144 +326,152 @@ func (r *Recipe) parseHTML() (rerr error) {
 		r = &Recipe{}
 	}
 	if r.FileContent == "" || r.FileName == "" {
-		rerr = fmt.Errorf("no file loaded")
+		rerr = ErrNoContent
 		return
 	}
 
-	r.Lines, rerr = getIngredientLinesInHTML(r.FileContent)
+	r.Lines, rerr = r.getParser().getIngredientLinesInHTML(hostnameOf(r.FileName), r.FileContent)
+	schemaRecipe, _ := findSchemaRecipe(r.FileContent)
+	r.Metadata = parseMetadata(schemaRecipe)
+	r.Directions = getDirectionsInHTML(r.FileContent, schemaRecipe)
 	return r.parseRecipe(true) // Enforce minimum 3 ingredients for HTML recipes
 
 }
 
 func (r *Recipe) parseRecipe(enforceMinimum bool) (rerr error) {
-	goodLines := make([]LineInfo, len(r.Lines))
-	j := 0
+	p := r.getParser()
+	goodLines := make([]LineInfo, 0, len(r.Lines))
+	r.Rejected = nil
+	reject := func(lineInfo LineInfo, reason string) {
+		lineInfo.addReason(reason)
+		r.Rejected = append(r.Rejected, lineInfo)
+	}
+	lines := make([]LineInfo, 0, len(r.Lines))
 	for _, lineInfo := range r.Lines {
+		lines = append(lines, p.splitAmounts(lineInfo)...)
+	}
+	for _, lineInfo := range lines {
 		// Be more lenient with length for schema.org ingredients (they can be verbose)
-		maxLength := 150
+		maxLength := p.maxLineLength
 		if lineInfo.Source == "schema.org" {
-			maxLength = 250
+			maxLength = p.maxSchemaLineLength
 		}
-		if len(strings.TrimSpace(lineInfo.Line)) < 3 || len(strings.TrimSpace(lineInfo.Line)) > maxLength {
+		if length := len(strings.TrimSpace(lineInfo.Line)); length < 3 {
+			reject(lineInfo, "line too short")
+			continue
+		} else if length > maxLength {
+			reject(lineInfo, fmt.Sprintf("line too long (%d > %d)", length, maxLength))
 			continue
 		}
 		if strings.Contains(strings.ToLower(lineInfo.Line), "serving size") {
+			reject(lineInfo, "serving size")
 			continue
 		}
 		if strings.Contains(strings.ToLower(lineInfo.Line), "yield") {
+			reject(lineInfo, "yield")
 			continue
 		}
 
 		// singularlize
 		lineInfo.Ingredient.Measure = Measure{}
 
-		// get amount, continue if there is an error (except for schema.org which allows no amount)
+		// get amount, continue if there is an error (except for schema.org
+		// which allows no amount, for lines seasoned to taste and for lines
+		// with ingredients joined by a conjunction, like "salt and pepper")
 		err := lineInfo.getTotalAmount()
 		if err != nil {
-			log.Tracef("[%s]: %s (%+v)", lineInfo.Line, err.Error(), lineInfo.AmountInString)
+			p.logger.Tracef("[%s]: %s (%+v)", lineInfo.Line, err.Error(), lineInfo.AmountInString)
 			// For non-schema.org sources, skip if no amount found
-			if lineInfo.Source != "schema.org" {
+			conjoined := len(lineInfo.IngredientsInString) > 1
+			if lineInfo.Source != "schema.org" && !conjoined && !p.toTaste(lineInfo.LineOriginal) {
+				reject(lineInfo, err.Error())
 				continue
 			}
+			lineInfo.addReason(err.Error())
 		}
 
 		// get ingredient, continue if its not found
-		err = lineInfo.getIngredient()
+		err = lineInfo.getIngredient(p)
 		if err != nil {
-			log.Tracef("[%s]: %s", lineInfo.Line, err.Error())
+			p.logger.Tracef("[%s]: %s", lineInfo.Line, err.Error())
 			// Even for schema.org, we need at least an ingredient name
+			reject(lineInfo, err.Error())
 			continue
 		}
+		// a line that is kept has at least its ingredient going for it
+		lineInfo.Confidence = math.Max(lineInfo.Confidence, 1.0/maxLineScore)
 
 		// get measure
 		err = lineInfo.getMeasure()
 		if err != nil {
-			log.Tracef("[%s]: %s", lineInfo.Line, err.Error())
+			p.logger.Tracef("[%s]: %s", lineInfo.Line, err.Error())
+			lineInfo.addReason(err.Error())
 		}
 
+		// read package sizes and notes in parentheses
+		notes := lineInfo.getParentheticals(p)
+
+		// combine "1 cup plus 2 tablespoons" into one measure
+		lineInfo.getCompoundMeasure(p)
+
 		// get comment
 		if len(lineInfo.MeasureInString) > 0 && len(lineInfo.IngredientsInString) > 0 {
-			lineInfo.Ingredient.Comment = getOtherInBetweenPositions(lineInfo.Line, lineInfo.MeasureInString[0], lineInfo.IngredientsInString[0])
+			lastMeasure := lineInfo.MeasureInString[0]
+			if parts := len(lineInfo.Ingredient.Measure.Parts); parts > 1 {
+				lastMeasure = lineInfo.MeasureInString[parts-1]
+			}
+			lineInfo.Ingredient.Comment = getOtherInBetweenPositions(lineInfo.Line, lastMeasure, lineInfo.IngredientsInString[0])
+		}
+		if len(notes) > 0 {
+			lineInfo.Ingredient.Comment = strings.TrimPrefix(lineInfo.Ingredient.Comment+", "+strings.Join(notes, ", "), ", ")
 		}
 
+		// get preparations, size and whether it is optional
+		lineInfo.getPreparation(p, notes)
+
 		// normalize into cups
-		lineInfo.Ingredient.Measure.Cups, err = normalizeIngredient(
-			lineInfo.Ingredient.Name,
-			lineInfo.Ingredient.Measure.Name,
-			lineInfo.Ingredient.Measure.Amount,
-		)
+		lineInfo.Ingredient.Measure.Cups, err = lineInfo.Ingredient.normalize(p)
 		if err != nil {
-			log.Tracef("[%s]: %s", lineInfo.LineOriginal, err.Error())
+			p.logger.Tracef("[%s]: %s", lineInfo.LineOriginal, err.Error())
+			lineInfo.addReason(err.Error())
 		} else {
-			log.Tracef("[%s]: %+v", lineInfo.LineOriginal, lineInfo)
+			p.logger.Tracef("[%s]: %+v", lineInfo.LineOriginal, lineInfo)
 		}
 
-		goodLines[j] = lineInfo
-		j++
-	}
-	r.Lines = goodLines[:j]
+		// weigh in grams
+		err = lineInfo.Ingredient.weigh(p)
+		if err != nil {
+			p.logger.Tracef("[%s]: %s", lineInfo.LineOriginal, err.Error())
+			lineInfo.addReason(err.Error())
+		}
 
-	// Ensure we still have at least 2 ingredients after filtering (only for HTML recipes)
-	if enforceMinimum && len(r.Lines) < 2 {
-		rerr = fmt.Errorf("insufficient ingredients found: %d (minimum 2 required)", len(r.Lines))
-		return
+		// split "salt and pepper" into a line for each ingredient
+		goodLines = append(goodLines, lineInfo.split(p)...)
 	}
+	r.Lines = goodLines
 
-	rerr = r.ConvertIngredients()
-	if rerr != nil {
+	// Ensure we still have enough ingredients after filtering (only for HTML recipes)
+	if enforceMinimum && len(r.Lines) < p.minIngredients {
+		rerr = &InsufficientIngredientsError{Found: len(r.Lines), Minimum: p.minIngredients}
 		return
 	}
 
 	// consolidate ingredients
-	ingredients := make(map[string]Ingredient)
-	ingredientList := []string{}
+	r.Ingredients = r.Consolidate(ConsolidateAcrossGroups)
+	r.Groups = nil
 	for _, line := range r.Lines {
-		if _, ok := ingredients[line.Ingredient.Name]; ok {
-			if ingredients[line.Ingredient.Name].Measure.Name == line.Ingredient.Measure.Name {
-				ingredients[line.Ingredient.Name] = Ingredient{
-					Name:    line.Ingredient.Name,
-					Comment: ingredients[line.Ingredient.Name].Comment,
-					Measure: Measure{
-						Name:   ingredients[line.Ingredient.Name].Measure.Name,
-						Amount: ingredients[line.Ingredient.Name].Measure.Amount + line.Ingredient.Measure.Amount,
-						Cups:   ingredients[line.Ingredient.Name].Measure.Cups + line.Ingredient.Measure.Cups,
-					},
-				}
-			} else {
-				ingredients[line.Ingredient.Name] = Ingredient{
-					Name:    line.Ingredient.Name,
-					Comment: ingredients[line.Ingredient.Name].Comment,
-					Measure: Measure{
-						Name:   ingredients[line.Ingredient.Name].Measure.Name,
-						Amount: ingredients[line.Ingredient.Name].Measure.Amount,
-						Cups:   ingredients[line.Ingredient.Name].Measure.Cups + line.Ingredient.Measure.Cups,
-					},
-				}
-			}
-		} else {
-			ingredientList = append(ingredientList, line.Ingredient.Name)
-			ingredients[line.Ingredient.Name] = Ingredient{
-				Name:    line.Ingredient.Name,
-				Comment: line.Ingredient.Comment,
-				Measure: Measure{
-					Name:   line.Ingredient.Measure.Name,
-					Amount: line.Ingredient.Measure.Amount,
-					Cups:   line.Ingredient.Measure.Cups,
-				},
-			}
+		if line.Ingredient.Group != "" {
+			r.Groups = r.IngredientGroups()
+			break
 		}
 	}
-	r.Ingredients = make([]Ingredient, len(ingredients))
-	for i, ing := range ingredientList {
-		r.Ingredients[i] = ingredients[ing]
-	}
 
 	return
 }
 
 // extractLinesFromSchemaOrg attempts to extract ingredients from schema.org Recipe markup
 // It looks for JSON-LD or Microdata with @type: Recipe and extracts recipeIngredient property
-func extractLinesFromSchemaOrg(htmlS string) (lineInfos []LineInfo, err error) {
+func (p *Parser) extractLinesFromSchemaOrg(htmlS string) (lineInfos []LineInfo, err error) {
 	// Parse the HTML for microdata/JSON-LD
 	// The last two parameters are contentType and baseURL which we can leave empty
 	data, err := microdata.ParseHTML(strings.NewReader(htmlS), "", "")
@@ -356,58 +485,73 @@ func extractLinesFromSchemaOrg(htmlS string) (lineInfos []LineInfo, err error) {
 			// Extract recipeIngredient properties (note: GetProperties returns all values for this property)
 			ingredients, ok := item.GetProperties("recipeIngredient")
 			if !ok || len(ingredients) == 0 {
-				log.Tracef("recipeIngredient property not found or empty")
+				p.logger.Tracef("recipeIngredient property not found or empty")
 				continue
 			}
 
-			log.Tracef("found %d recipeIngredient values", len(ingredients))
+			p.logger.Tracef("found %d recipeIngredient values", len(ingredients))
 
-			// Convert ingredient values to strings
+			// Convert ingredient values to strings, turning each HowToSection
+			// into a header line followed by its ingredients
 			var ingredientStrings []string
 			for _, ing := range ingredients {
 				if str, ok := ing.(string); ok {
 					ingredientStrings = append(ingredientStrings, str)
+				} else if section, ok := ing.(*microdata.Item); ok && section.IsOfSchemaType("HowToSection") {
+					ingredientStrings = append(ingredientStrings, sectionLines(itemToMap(section))...)
 				} else {
-					log.Tracef("skipping non-string ingredient: %T = %+v", ing, ing)
+					p.logger.Tracef("skipping non-string ingredient: %T = %+v", ing, ing)
 				}
 			}
 
 			if len(ingredientStrings) == 0 {
-				log.Tracef("no string ingredients found")
+				p.logger.Tracef("no string ingredients found")
 				continue
 			}
 
 			// Convert ingredient strings to LineInfo and populate analysis fields
 			for _, ingStr := range ingredientStrings {
 				sanitized := SanitizeLine(ingStr)
+				ingredientsInString := p.ingredientsTrie.findAll(sanitized)
+				if len(ingredientsInString) > 1 {
+					ingredientsInString = p.conjoin(sanitized, ingredientsInString, 0)
+				}
 				lineInfo := LineInfo{
 					LineOriginal:        ingStr,
 					Line:                sanitized,
-					IngredientsInString: GetIngredientsInString(sanitized),
-					AmountInString:      GetNumbersInString(sanitized),
-					MeasureInString:     GetMeasuresInString(sanitized),
+					IngredientsInString: ingredientsInString,
+					AmountInString:      p.numbersTrie.findAll(sanitized),
+					MeasureInString:     p.measuresTrie.findAll(sanitized),
 					Source:              "schema.org",
 				}
+				lineInfo.score()
 				lineInfos = append(lineInfos, lineInfo)
 			}
 
+			lineInfos = assignGroups(lineInfos, "")
+
 			// If we found ingredients, return them
 			if len(lineInfos) > 0 {
-				log.Tracef("extracted %d ingredients from schema.org Recipe", len(lineInfos))
+				p.logger.Tracef("extracted %d ingredients from schema.org Recipe", len(lineInfos))
 				return lineInfos, nil
 			}
 		}
 	}
 
 	// No Recipe found or no ingredients
-	return nil, fmt.Errorf("no schema.org Recipe with ingredients found")
+	return nil, fmt.Errorf("%w: no schema.org Recipe with ingredients", ErrNoRecipe)
 }
 
-func getIngredientLinesInHTML(htmlS string) (lineInfos []LineInfo, err error) {
-	// First try to extract from schema.org structured data
-	schemaLineInfos, schemaErr := extractLinesFromSchemaOrg(htmlS)
+func (p *Parser) getIngredientLinesInHTML(hostname, htmlS string) (lineInfos []LineInfo, err error) {
+	// Site-specific extractors know the page better than any heuristic
+	if extracted, ok := p.extractLinesWithExtractors(hostname, htmlS); ok {
+		return extracted, nil
+	}
+
+	// Then try to extract from schema.org structured data
+	schemaLineInfos, schemaErr := p.extractLinesFromSchemaOrg(htmlS)
 	if schemaErr == nil && len(schemaLineInfos) >= 2 {
-		log.Trace("using schema.org Recipe ingredients")
+		p.logger.Tracef("using schema.org Recipe ingredients")
 		return schemaLineInfos, nil
 	}
 
@@ -416,18 +560,21 @@ func getIngredientLinesInHTML(htmlS string) (lineInfos []LineInfo, err error) {
 	if err != nil {
 		return
 	}
+	// grouped blocks that were already taken as ingredient lines are not
+	// scored again as a single line of their parent
+	captured := make(map[*html.Node]bool)
 	var f func(n *html.Node, lineInfos *[]LineInfo) (s string, done bool)
 	f = func(n *html.Node, lineInfos *[]LineInfo) (s string, done bool) {
 		childrenLineInfo := []LineInfo{}
-		// log.Tracef("%+v", n)
+		// p.logger.Tracef("%+v", n)
 		score := 0
 		isScript := n.DataAtom == atom.Script
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
 			if isScript {
 				// try to capture JSON and if successful, do a hard exit
-				lis, errJSON := extractLinesFromJavascript(c.Data)
+				lis, errJSON := p.extractLinesFromJavascript(c.Data)
 				if errJSON == nil && len(lis) >= 2 {
-					log.Trace("got ingredients from JSON")
+					p.logger.Tracef("got ingredients from JSON")
 					*lineInfos = lis
 					done = true
 					return
@@ -438,16 +585,19 @@ func getIngredientLinesInHTML(htmlS string) (lineInfos []LineInfo, err error) {
 			if done {
 				return
 			}
-			if childText != "" {
-				scoreOfLine, lineInfo := scoreLine(childText)
+			if childText != "" && !captured[c] {
+				scoreOfLine, lineInfo := p.scoreLine(childText)
 				childrenLineInfo = append(childrenLineInfo, lineInfo)
 				score += scoreOfLine
 			}
 		}
 		if score > 2 && len(childrenLineInfo) < 25 && len(childrenLineInfo) >= 2 {
-			*lineInfos = append(*lineInfos, childrenLineInfo...)
-			for _, child := range childrenLineInfo {
-				log.Tracef("[%s]", child.LineOriginal)
+			title := groupTitleBefore(n)
+			grouped := assignGroups(append([]LineInfo{}, childrenLineInfo...), title)
+			*lineInfos = append(*lineInfos, grouped...)
+			captured[n] = title != ""
+			for _, child := range grouped {
+				p.logger.Tracef("[%s]", child.LineOriginal)
 			}
 		}
 		if len(childrenLineInfo) > 0 {
@@ -466,7 +616,7 @@ func getIngredientLinesInHTML(htmlS string) (lineInfos []LineInfo, err error) {
 	return
 }
 
-func extractLinesFromJavascript(jsString string) (lineInfo []LineInfo, err error) {
+func (p *Parser) extractLinesFromJavascript(jsString string) (lineInfo []LineInfo, err error) {
 
 	var arrayMap = []map[string]interface{}{}
 	var regMap = make(map[string]interface{})
@@ -480,37 +630,41 @@ func extractLinesFromJavascript(jsString string) (lineInfo []LineInfo, err error
 			err = fmt.Errorf("nothing to parse")
 			return
 		}
-		parseMap(arrayMap[0], &lineInfo)
+		p.parseMap(arrayMap[0], &lineInfo)
 		err = nil
 	} else {
-		parseMap(regMap, &lineInfo)
+		p.parseMap(regMap, &lineInfo)
 		err = nil
 	}
 
 	return
 }
 
-func parseMap(aMap map[string]interface{}, lineInfo *[]LineInfo) {
+func (p *Parser) parseMap(aMap map[string]interface{}, lineInfo *[]LineInfo) {
 	for _, val := range aMap {
 		switch val.(type) {
 		case map[string]interface{}:
-			parseMap(val.(map[string]interface{}), lineInfo)
+			p.parseMap(val.(map[string]interface{}), lineInfo)
 		case []interface{}:
-			parseArray(val.([]interface{}), lineInfo)
+			p.parseArray(val.([]interface{}), lineInfo)
 		default:
 			// fmt.Println(key, ":", concreteVal)
 		}
 	}
 }
 
-func parseArray(anArray []interface{}, lineInfo *[]LineInfo) {
+func (p *Parser) parseArray(anArray []interface{}, lineInfo *[]LineInfo) {
 	concreteLines := []string{}
 	for _, val := range anArray {
 		switch concreteVal := val.(type) {
 		case map[string]interface{}:
-			parseMap(val.(map[string]interface{}), lineInfo)
+			if hasSchemaType(concreteVal, "HowToSection") {
+				concreteLines = append(concreteLines, sectionLines(concreteVal)...)
+				continue
+			}
+			p.parseMap(val.(map[string]interface{}), lineInfo)
 		case []interface{}:
-			parseArray(val.([]interface{}), lineInfo)
+			p.parseArray(val.([]interface{}), lineInfo)
 		default:
 			switch v := concreteVal.(type) {
 			case string:
@@ -519,39 +673,49 @@ func parseArray(anArray []interface{}, lineInfo *[]LineInfo) {
 		}
 	}
 
-	score, li := scoreLines(concreteLines)
-	log.Trace(score, li)
+	score, li := p.scoreLines(concreteLines)
+	p.logger.Tracef("%d %v", score, li)
 	if score > 20 {
-		*lineInfo = li
+		*lineInfo = assignGroups(li, "")
 	}
 
 	return
 }
 
+// scoreLines scores lines with the default Parser
 func scoreLines(lines []string) (score int, lineInfo []LineInfo) {
+	return defaultParser.scoreLines(lines)
+}
+
+// scoreLine scores a line with the default Parser
+func scoreLine(line string) (score int, lineInfo LineInfo) {
+	return defaultParser.scoreLine(line)
+}
+
+func (p *Parser) scoreLines(lines []string) (score int, lineInfo []LineInfo) {
 	if len(lines) < 2 {
 		return
 	}
 	lineInfo = make([]LineInfo, len(lines))
 	for i, line := range lines {
 		var scored int
-		scored, lineInfo[i] = scoreLine(line)
+		scored, lineInfo[i] = p.scoreLine(line)
 		score += scored
 	}
 	return
 }
 
-func scoreLine(line string) (score int, lineInfo LineInfo) {
+func (p *Parser) scoreLine(line string) (score int, lineInfo LineInfo) {
 	lineInfo = LineInfo{}
 	lineInfo.LineOriginal = line
 	lineInfo.Line = SanitizeLine(line)
-	lineInfo.IngredientsInString = GetIngredientsInString(lineInfo.Line)
-	lineInfo.AmountInString = GetNumbersInString(lineInfo.Line)
-	lineInfo.MeasureInString = GetMeasuresInString(lineInfo.Line)
+	lineInfo.IngredientsInString = p.ingredientsTrie.findAll(lineInfo.Line)
+	lineInfo.AmountInString = p.numbersTrie.findAll(lineInfo.Line)
+	lineInfo.MeasureInString = p.measuresTrie.findAll(lineInfo.Line)
 	lineInfo.Source = "dom"
-	// When multiple ingredients are detected, keep only the longest/most specific one
-	// This avoids scoring penalties and selects the better match (e.g., "chocolate chip" over "milk")
-	// Since only IngredientsInString[0] is used downstream, we consolidate to a single element
+	// When multiple ingredients are detected, keep the longest/most specific one
+	// (e.g., "chocolate chip" over "milk") with the ingredients joined to it by
+	// "and" or "or". IngredientsInString[0] is the main ingredient of the line.
 	if len(lineInfo.IngredientsInString) > 1 {
 		longestIdx := 0
 		for i := 1; i < len(lineInfo.IngredientsInString); i++ {
@@ -559,42 +723,53 @@ func scoreLine(line string) (score int, lineInfo LineInfo) {
 				longestIdx = i
 			}
 		}
-		lineInfo.IngredientsInString = []WordPosition{lineInfo.IngredientsInString[longestIdx]}
+		lineInfo.IngredientsInString = p.conjoin(lineInfo.Line, lineInfo.IngredientsInString, longestIdx)
 	}
 
+	// long lines are more likely prose than ingredients, so they do not
+	// count toward finding the ingredients, but still get a confidence
+	score = lineInfo.score()
 	if len(lineInfo.LineOriginal) > 50 {
-		return
-	}
-
-	// does it contain an ingredient?
-	if len(lineInfo.IngredientsInString) > 0 {
-		score++
+		score = 0
 	}
+	return
+}
 
-	// disfavor containing multiple ingredients
-	if len(lineInfo.IngredientsInString) > 1 {
-		score = score - len(lineInfo.IngredientsInString) + 1
+// maxLineScore is the score of a line that looks like an ingredient in
+// every way, which has a confidence of 1
+const maxLineScore = 6
+
+// score scores how much a line looks like an ingredient, setting its
+// Confidence and the Reasons for a lower one
+func (lineInfo *LineInfo) score() (score int) {
+	hasIngredient := len(lineInfo.IngredientsInString) > 0
+	hasAmount := len(lineInfo.AmountInString) > 0
+	hasMeasure := len(lineInfo.MeasureInString) > 0
+	check := func(ok bool, reason string) {
+		if ok {
+			score++
+		} else {
+			lineInfo.addReason(reason)
+		}
 	}
 
+	// does it contain an ingredient?
+	check(hasIngredient, "no ingredient found")
 	// does it contain an amount?
-	if len(lineInfo.AmountInString) > 0 {
-		score++
-	}
+	check(hasAmount, "no amount found")
 	// does it contain a measure (cups, tsps)?
-	if len(lineInfo.MeasureInString) > 0 {
-		score++
-	}
+	check(hasMeasure, "no measure found")
 	// does the ingredient come after the measure?
-	if len(lineInfo.IngredientsInString) > 0 && len(lineInfo.MeasureInString) > 0 && lineInfo.IngredientsInString[0].Position > lineInfo.MeasureInString[0].Position {
-		score++
+	if hasIngredient && hasMeasure {
+		check(lineInfo.IngredientsInString[0].Position > lineInfo.MeasureInString[0].Position, "ingredient before the measure")
 	}
 	// does the ingredient come after the amount?
-	if len(lineInfo.IngredientsInString) > 0 && len(lineInfo.AmountInString) > 0 && lineInfo.IngredientsInString[0].Position > lineInfo.AmountInString[0].Position {
-		score++
+	if hasIngredient && hasAmount {
+		check(lineInfo.IngredientsInString[0].Position > lineInfo.AmountInString[0].Position, "ingredient before the amount")
 	}
 	// does the measure come after the amount?
-	if len(lineInfo.MeasureInString) > 0 && len(lineInfo.AmountInString) > 0 && lineInfo.MeasureInString[0].Position > lineInfo.AmountInString[0].Position {
-		score++
+	if hasMeasure && hasAmount {
+		check(lineInfo.MeasureInString[0].Position > lineInfo.AmountInString[0].Position, "measure before the amount")
 	}
 
 	// disfavor lots of puncuation
@@ -602,12 +777,14 @@ func scoreLine(line string) (score int, lineInfo LineInfo) {
 	for _, punc := range puncuation {
 		if strings.Count(lineInfo.LineOriginal, punc) > 1 {
 			score--
+			lineInfo.addReason(fmt.Sprintf("more than one '%s'", punc))
 		}
 	}
 
 	// disfavor long lines
 	if len(lineInfo.Line) > 40 {
 		score = score - (len(lineInfo.Line) - 40)
+		lineInfo.addReason(fmt.Sprintf("long line (%d characters)", len(lineInfo.Line)))
 	}
 	if len(lineInfo.Line) > 250 {
 		score = 0
@@ -621,18 +798,23 @@ func scoreLine(line string) (score int, lineInfo LineInfo) {
 	// if only one thing is right, its wrong
 	if score == 1 {
 		score = 0.0
+		lineInfo.addReason("only one sign of an ingredient")
 	}
+	lineInfo.Confidence = math.Max(0, math.Min(1, float64(score)/maxLineScore))
 	return
 }
 
-func (r *Recipe) ConvertIngredients() (err error) {
-
-	return
+// addReason notes what lowered the confidence in a line or why it was
+// rejected
+func (lineInfo *LineInfo) addReason(reason string) {
+	if !slices.Contains(lineInfo.Reasons, reason) {
+		lineInfo.Reasons = append(lineInfo.Reasons, reason)
+	}
 }
 
 // IngredientList will return a string containing the ingredient list
 func (r *Recipe) IngredientList() (ingredientList IngredientList) {
-	ingredientList = IngredientList{make([]Ingredient, len(r.Lines))}
+	ingredientList = IngredientList{Ingredients: make([]Ingredient, len(r.Lines)), parser: r.parser}
 	for i, li := range r.Lines {
 		ingredientList.Ingredients[i] = li.Ingredient
 		ingredientList.Ingredients[i].Line = li.LineOriginal
@@ -643,25 +825,41 @@ func (r *Recipe) IngredientList() (ingredientList IngredientList) {
 func (lineInfo *LineInfo) getTotalAmount() (err error) {
 	lastPosition := -1
 	totalAmount := 0.0
+	minAmount := -1.0 // lower end of a range like "2 to 3"
 	wps := lineInfo.AmountInString
+	runes := []rune(lineInfo.Line)
 
 	// Try corpus-based number detection first
 	for i := range wps {
 		wps[i].Word = strings.TrimSpace(wps[i].Word)
 		if lastPosition == -1 {
 			totalAmount = ConvertStringToNumber(wps[i].Word)
-		} else if math.Abs(float64(wps[i].Position-lastPosition)) < 6 {
+		} else if math.Abs(float64(wps[i].Position-lastPosition)) < 6 && !lineInfo.measureBetween(lastPosition, wps[i].Position) {
+			if minAmount < 0 && isRangeSeparator(runes, lastPosition+1, wps[i].Position+1) {
+				minAmount = totalAmount
+				totalAmount = 0
+			}
 			totalAmount += ConvertStringToNumber(wps[i].Word)
 		}
 		// Use rune length since Position is rune-based (from trie)
 		lastPosition = wps[i].Position + len([]rune(wps[i].Word))
 	}
 
+	// In "60 grams plus 2 tablespoons" the corpus only finds the amount of the
+	// second measure, so the amount at the start is left for the regex
+	if len(wps) > 0 && len(lineInfo.MeasureInString) > 0 && wps[0].Position > lineInfo.MeasureInString[0].Position && reNumberAtStart.MatchString(lineInfo.Line) {
+		totalAmount = 0
+		minAmount = -1
+	}
+
 	// If corpus didn't find numbers, try regex (for numbers >20 not in corpus)
 	if totalAmount == 0 {
 		// Match integers and decimals at the start of the line
 		matches := reNumberAtStart.FindStringSubmatch(lineInfo.Line)
-		if len(matches) > 1 {
+		if rangeMatches := reRangeAtStart.FindStringSubmatch(lineInfo.Line); len(rangeMatches) > 2 {
+			minAmount, _ = strconv.ParseFloat(rangeMatches[1], 64)
+			totalAmount, _ = strconv.ParseFloat(rangeMatches[2], 64)
+		} else if len(matches) > 1 {
 			numStr := strings.TrimSpace(matches[1])
 			// Try to parse as float first
 			if val, parseErr := strconv.ParseFloat(numStr, 64); parseErr == nil {
@@ -677,6 +875,15 @@ func (lineInfo *LineInfo) getTotalAmount() (err error) {
 		totalAmount = 1
 	}
 
+	// Amount is the upper end of a range so totals are never short
+	if minAmount > 0 && totalAmount > minAmount {
+		lineInfo.Ingredient.Measure.Min = minAmount
+		lineInfo.Ingredient.Measure.Max = totalAmount
+	} else if minAmount > 0 {
+		totalAmount = minAmount
+	}
+	lineInfo.Ingredient.Measure.Approximate = reApproximate.MatchString(reParentheses.ReplaceAllString(lineInfo.LineOriginal, " "))
+
 	// For schema.org ingredients, allow zero amounts (e.g., "salt" or "to taste")
 	if totalAmount == 0 {
 		if lineInfo.Source == "schema.org" {
@@ -684,7 +891,7 @@ func (lineInfo *LineInfo) getTotalAmount() (err error) {
 			lineInfo.Ingredient.Measure.Amount = 0
 			err = nil
 		} else {
-			err = fmt.Errorf("no amount found")
+			err = ErrNoAmount
 		}
 	} else {
 		lineInfo.Ingredient.Measure.Amount = totalAmount
@@ -692,12 +899,20 @@ func (lineInfo *LineInfo) getTotalAmount() (err error) {
 	return
 }
 
-func (lineInfo *LineInfo) getIngredient() (err error) {
+func (lineInfo *LineInfo) getIngredient(p *Parser) (err error) {
+	if p.fuzzyTree != nil {
+		if match, position, ok := p.fuzzyIngredient(lineInfo); ok {
+			lineInfo.IngredientsInString = []WordPosition{{Word: match.Corrected, Position: position}}
+			lineInfo.Fuzzy = &match
+		}
+	}
 	if len(lineInfo.IngredientsInString) == 0 {
-		err = fmt.Errorf("no ingredient found")
+		err = ErrNoIngredient
 		return
 	}
-	lineInfo.Ingredient.Name = inflection.Singular(lineInfo.IngredientsInString[0].Word)
+	lineInfo.Ingredient.Name = p.singular(lineInfo.IngredientsInString[0].Word)
+	lineInfo.Ingredient.ID = p.canonical(lineInfo.Ingredient.Name)
+	lineInfo.Ingredient.Category = p.category(lineInfo.Ingredient.Name)
 	return
 }
 
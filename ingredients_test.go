@@ -43,7 +43,7 @@ var ts = []URLIngredients{
 	//
 	{
 		"https://www.wifemamafoodie.com/cinnamon-rolls/index.html",
-		[]string{"2 1/4 teaspoon yeast", "1 teaspoon salt", "1/3 cup avocado oil", "1/4 cup maple syrup", "3/4 cup coconut sugar", "1 tablespoon cinnamon", "4 oz. cream cheese", "1 tablespoon butter", "2 tablespoons maple syrup", "1/2 cup butter", "1 cup powdered sugar", "1 teaspoon vanilla", "1 tablespoons milk"},
+		[]string{"3–4 1/4 cups flour", "2 1/4 teaspoon yeast", "1 teaspoon salt", "3/4 cup almond milk", "1/2 cup water", "1/3 cup avocado oil", "1/4 cup maple syrup", "1/2 cup butter", "3/4 cup coconut sugar", "1 tablespoon cinnamon", "4 oz. cream cheese", "1 tablespoon butter", "2–3 tablespoons maple syrup", "1/2 cup butter", "1 cup powdered sugar", "1 teaspoon vanilla", "1–2 tablespoons milk"},
 	},
 	{
 		"https://www.goldenmalted.com/gingerbread-waffle-recipe",
@@ -63,7 +63,7 @@ var ts = []URLIngredients{
 	},
 	{
 		"https://cooking.nytimes.com/recipes/1012904-banana-everything-cookies",
-		[]string{"0 whole cooking oil", "1 whole banana", "1/3 cup canola oil", "2/3 cup sugar", "1 teaspoon vanilla", "3/4 cup flour", "1/2 teaspoon baking soda", "1/4 teaspoon salt", "1/4 teaspoon cinnamon", "2 cups oatmeal", "1/2 cup walnut", "1/2 cup chocolate chip"},
+		[]string{"0 whole cooking oil", "1 whole banana", "1/3 cup canola oil", "2/3 cup sugar", "1 teaspoon vanilla", "7/8 cup flour", "1/2 teaspoon baking soda", "1/4 teaspoon salt", "1/4 teaspoon cinnamon", "2 cups oatmeal", "1/2 cup walnut", "1/2 cup chocolate chip"},
 	},
 	{
 		"https://www.realmomnutrition.com/banana-bread-with-chocolate-chips/index.html",
@@ -79,7 +79,7 @@ var ts = []URLIngredients{
 	},
 	{
 		"https://www.bonappetit.com/recipe/bas-best-chocolate-chip-cookies",
-		[]string{"1 1/4 tsp. salt", "3/4 tsp. baking soda", "3/4 cup butter", "1 cup brown sugar", "1/4 cup sugar", "1 whole egg", "2 whole egg yolk", "2 tsp. vanilla", "6 oz. chocolate chip"},
+		[]string{"1 1/2 cups flour", "1 1/4 tsp. salt", "3/4 tsp. baking soda", "3/4 cup butter", "1 cup brown sugar", "1/4 cup sugar", "1 whole egg", "2 whole egg yolk", "2 tsp. vanilla", "6 oz. chocolate"},
 	},
 	{
 		"https://pinchofyum.com/the-best-soft-chocolate-chip-cookies",
@@ -87,7 +87,7 @@ var ts = []URLIngredients{
 	},
 	{
 		"https://www.modernhoney.com/the-best-chocolate-chip-cookies/",
-		[]string{"1 cup butter", "1 cup brown sugar", "1/2 cup sugar", "2 whole egg", "2 teaspoons vanilla", "2 3/4 cups flour", "1 teaspoon cornstarch", "3/4 teaspoon baking soda", "3/4 teaspoon salt", "2 1/2 cups chocolate chip"},
+		[]string{"1 cup butter", "1 cup brown sugar", "5/8 cup sugar", "2 whole egg", "2 teaspoons vanilla", "2 3/4 cups flour", "1 teaspoon cornstarch", "3/4 teaspoon baking soda", "3/4 teaspoon salt", "2–2 1/2 cups chocolate chip"},
 	},
 	{
 		"https://laurenslatest.com/actually-perfect-chocolate-chip-cookies/",
@@ -131,7 +131,7 @@ func TestTable(t *testing.T) {
 		assert.Nil(t, err)
 		ingredients := make([]string, len(r.IngredientList().Ingredients))
 		for i, ing := range r.IngredientList().Ingredients {
-			ingredients[i] = fmt.Sprintf("%s %s %s", AmountToString(ing.Measure.Amount), ing.Measure.Name, ing.Name)
+			ingredients[i] = fmt.Sprintf("%s %s %s", ing.Measure.AmountString(), ing.Measure.Name, ing.Name)
 		}
 		assert.Equal(t, t0.Ingredients, ingredients)
 	}
@@ -182,12 +182,12 @@ func TestJSON1(t *testing.T) {
 	if err != nil {
 		fmt.Println(err)
 	}
-	ingredients := `1/2 cup butter (unsalted)
+	ingredients := `1/2 cup butter (unsalted, 1 stick)
 3/4 cup brown sugar (packed dark)
 3/4 cup sugar
 2 whole eggs
 1 teaspoon vanilla (pure)
-1 whole chocolate chip
+1 (12 ounce) bag chocolate chip
 2 1/4 cups flour (all purpose)
 3/4 teaspoon baking soda
 1 teaspoon salt (fine)`
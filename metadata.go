@@ -0,0 +1,240 @@
+package ingredients
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata is the schema.org information about a recipe
+type Metadata struct {
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Authors     []string      `json:"authors,omitempty"`
+	Images      []string      `json:"images,omitempty"`
+	Yield       Yield         `json:"yield,omitempty"`
+	PrepTime    time.Duration `json:"prep_time,omitempty"`
+	CookTime    time.Duration `json:"cook_time,omitempty"`
+	TotalTime   time.Duration `json:"total_time,omitempty"`
+	Keywords    []string      `json:"keywords,omitempty"`
+	Cuisine     []string      `json:"cuisine,omitempty"`
+	Category    []string      `json:"category,omitempty"`
+	Rating      Rating        `json:"rating,omitempty"`
+}
+
+// Yield is the parsed recipeYield, e.g. "24 cookies" or "6-8 servings"
+type Yield struct {
+	Amount   float64 `json:"amount"`
+	Max      float64 `json:"max,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+	Original string  `json:"original,omitempty"`
+}
+
+// Rating is the schema.org aggregateRating
+type Rating struct {
+	Value float64 `json:"value"`
+	Count int     `json:"count"`
+}
+
+var (
+	reISODuration = regexp.MustCompile(`(?i)^P(?:([\d.]+)Y)?(?:([\d.]+)M)?(?:([\d.]+)W)?(?:([\d.]+)D)?(?:T(?:([\d.]+)H)?(?:([\d.]+)M)?(?:([\d.]+)S)?)?$`)
+	reYield       = regexp.MustCompile(`(\d+(?:\.\d+)?)(?:\s*(?:-|–|to)\s*(\d+(?:\.\d+)?))?`)
+	reYieldNote   = regexp.MustCompile(`\([^)]*\)?`)
+)
+
+// isoDurationUnits are the lengths of the designators in reISODuration, in order
+var isoDurationUnits = []time.Duration{
+	365 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	24 * time.Hour,
+	time.Hour,
+	time.Minute,
+	time.Second,
+}
+
+// ParseISODuration parses an ISO-8601 duration like "PT15M" or "P0Y0M0DT0H15M0.000S"
+func ParseISODuration(s string) (d time.Duration, err error) {
+	s = strings.TrimSpace(s)
+	matches := reISODuration.FindStringSubmatch(s)
+	if matches == nil || s == "P" || strings.HasSuffix(s, "T") {
+		err = fmt.Errorf("%w '%s'", ErrInvalidDuration, s)
+		return
+	}
+	for i, unit := range isoDurationUnits {
+		if matches[i+1] == "" {
+			continue
+		}
+		v, errParse := strconv.ParseFloat(matches[i+1], 64)
+		if errParse != nil {
+			err = fmt.Errorf("%w '%s'", ErrInvalidDuration, s)
+			return
+		}
+		d += time.Duration(math.Round(v * float64(unit)))
+	}
+	return
+}
+
+// ParseYield parses a recipeYield string like "About 2 dozen cookies" or "Serves 4"
+func ParseYield(s string) (y Yield) {
+	y.Original = strings.TrimSpace(s)
+	loc := reYield.FindStringSubmatchIndex(y.Original)
+	if loc == nil {
+		return
+	}
+	y.Amount, _ = strconv.ParseFloat(y.Original[loc[2]:loc[3]], 64)
+	if loc[4] >= 0 {
+		y.Max, _ = strconv.ParseFloat(y.Original[loc[4]:loc[5]], 64)
+	}
+
+	rest := strings.Fields(reYieldNote.ReplaceAllString(strings.ToLower(y.Original[loc[1]:]), " "))
+	if len(rest) > 0 && rest[0] == "dozen" {
+		y.Amount *= 12
+		y.Max *= 12
+		rest = rest[1:]
+	}
+	unit := strings.Join(rest, " ")
+	if i := strings.IndexAny(unit, ",;"); i >= 0 {
+		unit = unit[:i]
+	}
+	y.Unit = strings.TrimSpace(unit)
+	if y.Unit == "" {
+		y.Unit = "servings"
+	}
+	return
+}
+
+// parseMetadata reads the metadata out of a schema.org Recipe
+func parseMetadata(recipe map[string]interface{}) (m Metadata) {
+	if recipe == nil {
+		return
+	}
+	m.Name = strings.TrimSpace(stripTags(firstString(recipe["name"])))
+	m.Description = strings.TrimSpace(stripTags(firstString(recipe["description"])))
+	m.Authors = schemaNames(recipe["author"])
+	m.Images = schemaURLs(recipe["image"])
+	m.Keywords = schemaList(recipe["keywords"])
+	m.Cuisine = schemaList(recipe["recipeCuisine"])
+	m.Category = schemaList(recipe["recipeCategory"])
+	m.PrepTime, _ = ParseISODuration(firstString(recipe["prepTime"]))
+	m.CookTime, _ = ParseISODuration(firstString(recipe["cookTime"]))
+	m.TotalTime, _ = ParseISODuration(firstString(recipe["totalTime"]))
+
+	// prefer the yield that says what it is, e.g. ["24", "24 cookies"]
+	for _, yield := range schemaStrings(recipe["recipeYield"]) {
+		y := ParseYield(yield)
+		if y.Amount == 0 {
+			continue
+		}
+		if m.Yield.Amount == 0 || (m.Yield.Unit == "servings" && y.Unit != "servings") {
+			m.Yield = y
+		}
+	}
+
+	if rating, ok := recipe["aggregateRating"].(map[string]interface{}); ok {
+		m.Rating.Value = schemaNumber(rating["ratingValue"])
+		m.Rating.Count = int(schemaNumber(rating["ratingCount"]))
+		if m.Rating.Count == 0 {
+			m.Rating.Count = int(schemaNumber(rating["reviewCount"]))
+		}
+	}
+	return
+}
+
+// schemaStrings flattens a schema.org value into its string and number values
+func schemaStrings(v interface{}) (s []string) {
+	switch val := v.(type) {
+	case string:
+		s = append(s, val)
+	case float64:
+		s = append(s, strconv.FormatFloat(val, 'f', -1, 64))
+	case int:
+		s = append(s, strconv.Itoa(val))
+	case []interface{}:
+		for _, item := range val {
+			s = append(s, schemaStrings(item)...)
+		}
+	}
+	return
+}
+
+func firstString(v interface{}) string {
+	s := schemaStrings(v)
+	if len(s) == 0 {
+		return ""
+	}
+	return s[0]
+}
+
+// schemaList splits comma-separated schema.org values into a unique list
+func schemaList(v interface{}) (list []string) {
+	seen := make(map[string]struct{})
+	for _, s := range schemaStrings(v) {
+		for _, item := range strings.Split(s, ",") {
+			item = strings.TrimSpace(item)
+			if _, ok := seen[strings.ToLower(item)]; ok || item == "" {
+				continue
+			}
+			seen[strings.ToLower(item)] = struct{}{}
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+// schemaNames returns the names of a Person/Organization value
+func schemaNames(v interface{}) (names []string) {
+	switch val := v.(type) {
+	case string:
+		if strings.TrimSpace(val) != "" {
+			names = append(names, strings.TrimSpace(val))
+		}
+	case map[string]interface{}:
+		names = schemaNames(val["name"])
+	case []interface{}:
+		for _, item := range val {
+			names = append(names, schemaNames(item)...)
+		}
+	}
+	return
+}
+
+// schemaURLs returns the urls of an image value
+func schemaURLs(v interface{}) (urls []string) {
+	switch val := v.(type) {
+	case string:
+		if strings.TrimSpace(val) != "" {
+			urls = append(urls, strings.TrimSpace(val))
+		}
+	case map[string]interface{}:
+		urls = schemaURLs(val["url"])
+		if len(urls) == 0 {
+			urls = schemaURLs(val["contentUrl"])
+		}
+	case []interface{}:
+		for _, item := range val {
+			urls = append(urls, schemaURLs(item)...)
+		}
+	}
+	return
+}
+
+func schemaNumber(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case int:
+		return float64(val)
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f
+	case []interface{}:
+		if len(val) > 0 {
+			return schemaNumber(val[0])
+		}
+	}
+	return 0
+}
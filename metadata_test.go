@@ -0,0 +1,87 @@
+package ingredients
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"PT15M", 15 * time.Minute},
+		{"PT65M", 65 * time.Minute},
+		{"P0Y0M0DT0H15M0.000S", 15 * time.Minute},
+		{"P0Y0M0DT1H5M0.000S", time.Hour + 5*time.Minute},
+		{"PT1H30M", 90 * time.Minute},
+		{"P1DT2H", 26 * time.Hour},
+		{"PT0.5S", 500 * time.Millisecond},
+	}
+	for _, test := range tests {
+		d, err := ParseISODuration(test.input)
+		assert.Nil(t, err, test.input)
+		assert.Equal(t, test.expected, d, test.input)
+	}
+
+	for _, bad := range []string{"", "P", "PT", "15 minutes", "T15M"} {
+		_, err := ParseISODuration(bad)
+		assert.NotNil(t, err, bad)
+	}
+}
+
+func TestParseYield(t *testing.T) {
+	tests := []struct {
+		input  string
+		amount float64
+		max    float64
+		unit   string
+	}{
+		{"30 cookies", 30, 0, "cookies"},
+		{"About 2 dozen cookies", 24, 0, "cookies"},
+		{"Serves 2", 2, 0, "servings"},
+		{"6-8 servings", 6, 8, "servings"},
+		{"1 Loaf", 1, 0, "loaf"},
+		{`1 dozen large (4 1/2") pancakes`, 12, 0, "large pancakes"},
+		{"12", 12, 0, "servings"},
+	}
+	for _, test := range tests {
+		y := ParseYield(test.input)
+		assert.Equal(t, test.amount, y.Amount, test.input)
+		assert.Equal(t, test.max, y.Max, test.input)
+		assert.Equal(t, test.unit, y.Unit, test.input)
+		assert.Equal(t, test.input, y.Original)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	htmlString := `<html><body><script>
+	[{"@context":"http://schema.org","@type":"Recipe","name":"Chocolate Chip Cookies","description":"This is such an easy chocolate chip cookie.","author":[{"@type":"Person","name":"Food Network Kitchen"}],"image":{"@type":"ImageObject","url":"https://example.com/cookies.jpeg"},"keywords":"Easy Dessert Recipes,Dessert,Easy,Cookie","cookTime":"P0Y0M0DT0H15M0.000S","prepTime":"P0Y0M0DT0H20M0.000S","totalTime":"P0Y0M0DT1H5M0.000S","recipeIngredient":["1/2 cup unsalted butter","3/4 cup packed dark brown sugar","3/4 cup sugar","2 large egg","1 teaspoon pure vanilla extract","2 1/4 cups all-purpose flour","3/4 teaspoon baking soda","1 teaspoon fine salt"],"aggregateRating":{"@type":"AggregateRating","ratingValue":4.2,"reviewCount":536},"recipeYield":"30 cookies","recipeCuisine":"american","recipeCategory":"dessert"}]
+	</script></body></html>`
+	r, err := NewFromString(htmlString)
+	assert.Nil(t, err)
+	assert.Equal(t, Metadata{
+		Name:        "Chocolate Chip Cookies",
+		Description: "This is such an easy chocolate chip cookie.",
+		Authors:     []string{"Food Network Kitchen"},
+		Images:      []string{"https://example.com/cookies.jpeg"},
+		Yield:       Yield{Amount: 30, Unit: "cookies", Original: "30 cookies"},
+		PrepTime:    20 * time.Minute,
+		CookTime:    15 * time.Minute,
+		TotalTime:   65 * time.Minute,
+		Keywords:    []string{"Easy Dessert Recipes", "Dessert", "Easy", "Cookie"},
+		Cuisine:     []string{"american"},
+		Category:    []string{"dessert"},
+		Rating:      Rating{Value: 4.2, Count: 536},
+	}, r.Metadata)
+
+	// metadata should survive a save and load
+	fname := path.Join(t.TempDir(), "recipe.json")
+	assert.Nil(t, r.Save(fname))
+	r2, err := Load(fname)
+	assert.Nil(t, err)
+	assert.Equal(t, r.Metadata, r2.Metadata)
+}
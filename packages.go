@@ -0,0 +1,102 @@
+package ingredients
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// Package is the size of the packages in a line like "2 (14-ounce) cans tomatoes"
+type Package struct {
+	Count float64 `json:"count"`
+	Size  float64 `json:"size"`
+	Unit  string  `json:"unit"`
+	Name  string  `json:"name,omitempty"` // "can", "bag", ...
+}
+
+// String renders the package as written, e.g. "2 (14 ounce) cans"
+func (p Package) String() string {
+	s := AmountToString(p.Count) + " (" + AmountToString(p.Size) + " " + p.Unit + ")"
+	if p.Name != "" {
+		name := p.Name
+		if p.Count > 1 {
+			name = inflection.Plural(name)
+		}
+		s += " " + name
+	}
+	return s
+}
+
+var (
+	reParenthetical         = regexp.MustCompile(`\(([^()]*)\)`)
+	reParentheticalQuantity = regexp.MustCompile(`^(\d+\s+\d+/\d+|\d+/\d+|\d+(?:\.\d+)?)\s*-?\s*([a-z]+)\.?$`)
+)
+
+// packageNames are the containers that can follow a package size
+var packageNames = map[string]bool{
+	"bag":       true,
+	"bar":       true,
+	"block":     true,
+	"bottle":    true,
+	"box":       true,
+	"can":       true,
+	"carton":    true,
+	"container": true,
+	"envelope":  true,
+	"jar":       true,
+	"package":   true,
+	"packet":    true,
+	"pkg":       true,
+	"tub":       true,
+}
+
+// getParentheticals reads the parentheses that SanitizeLine removes. A
+// quantity right after the amount, like "1 (12-ounce) bag", is the size
+// of a package and the measure becomes the total of all the packages.
+// Every other parenthetical, like "(1 stick)" or "(softened)", is
+// returned as a note for the comment.
+func (lineInfo *LineInfo) getParentheticals(p *Parser) (notes []string) {
+	for _, loc := range reParenthetical.FindAllStringSubmatchIndex(lineInfo.LineOriginal, -1) {
+		inside := strings.TrimSpace(lineInfo.LineOriginal[loc[2]:loc[3]])
+		if inside == "" {
+			continue
+		}
+		if lineInfo.Ingredient.Measure.Package == nil && lineInfo.getPackage(p, inside, lineInfo.LineOriginal[:loc[0]], lineInfo.LineOriginal[loc[1]:]) {
+			continue
+		}
+		notes = append(notes, inside)
+	}
+	return
+}
+
+// getPackage sets the package of the measure when the parenthetical is a
+// size that follows nothing but the number of packages
+func (lineInfo *LineInfo) getPackage(p *Parser, inside, before, after string) bool {
+	measure := &lineInfo.Ingredient.Measure
+	count, ok := sumNumbers(strings.Fields(SanitizeLine(before)))
+	if !ok || count != measure.Amount {
+		return false
+	}
+	matches := reParentheticalQuantity.FindStringSubmatch(strings.ToLower(inside))
+	if matches == nil {
+		return false
+	}
+	unit := matches[2]
+	if _, ok := p.corpus.Measures[unit]; !ok {
+		return false
+	}
+	size, ok := sumNumbers(strings.Fields(SanitizeLine(matches[1])))
+	if !ok {
+		return false
+	}
+
+	pkg := &Package{Count: count, Size: size, Unit: unit}
+	if fields := strings.Fields(SanitizeLine(after)); len(fields) > 0 && packageNames[inflection.Singular(fields[0])] {
+		pkg.Name = inflection.Singular(fields[0])
+	}
+	measure.Package = pkg
+	measure.Amount = count * size
+	measure.Name = unit
+	return true
+}
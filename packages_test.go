@@ -0,0 +1,58 @@
+package ingredients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackages(t *testing.T) {
+	tests := []struct {
+		input   string
+		amount  float64
+		name    string
+		pkg     *Package
+		comment string
+	}{
+		{"1 (12-ounce) bag semisweet chocolate chips", 12, "ounce", &Package{Count: 1, Size: 12, Unit: "ounce", Name: "bag"}, ""},
+		{"2 (14.5 oz) cans diced tomatoes", 29, "oz", &Package{Count: 2, Size: 14.5, Unit: "oz", Name: "can"}, "diced"},
+		{"1 (1 lb) box spaghetti", 1, "lb", &Package{Count: 1, Size: 1, Unit: "lb", Name: "box"}, ""},
+		{"1/2 cup (1 stick) unsalted butter", 0.5, "cup", nil, "unsalted, 1 stick"},
+		{"1 cup (200 g) sugar", 1, "cup", nil, "200 g"},
+		{"2 tablespoons butter (softened)", 2, "tablespoons", nil, "softened"},
+		// each parenthetical on its own
+		{"1/2 cup (1 stick) butter (softened)", 0.5, "cup", nil, "1 stick, softened"},
+		{"1 (12-ounce) bag chocolate chips (about 2 cups)", 12, "ounce", &Package{Count: 1, Size: 12, Unit: "ounce", Name: "bag"}, "about 2 cups"},
+	}
+	for _, test := range tests {
+		r := &Recipe{FileName: "lines", FileContent: test.input}
+		_, lineInfo := scoreLine(test.input)
+		r.Lines = []LineInfo{lineInfo}
+		assert.Nil(t, r.parseRecipe(false))
+		if !assert.Equal(t, 1, len(r.Lines), test.input) {
+			continue
+		}
+		ing := r.Lines[0].Ingredient
+		assert.Equal(t, test.amount, ing.Measure.Amount, test.input)
+		assert.Equal(t, test.name, ing.Measure.Name, test.input)
+		assert.Equal(t, test.pkg, ing.Measure.Package, test.input)
+		assert.Equal(t, test.comment, ing.Comment, test.input)
+		if test.pkg != nil {
+			assert.Greater(t, ing.Measure.Cups, 0.0, test.input)
+		}
+	}
+}
+
+func TestTwoParentheticals(t *testing.T) {
+	ingredientList, err := ParseTextIngredients("1/2 cup (1 stick) butter (softened)\n1 cup water")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ingredientList.Ingredients))
+	assert.Equal(t, "butter", ingredientList.Ingredients[0].Name)
+	assert.Equal(t, "1 stick, softened", ingredientList.Ingredients[0].Comment)
+}
+
+func TestPackageString(t *testing.T) {
+	assert.Equal(t, "1 (12 ounce) bag", Package{Count: 1, Size: 12, Unit: "ounce", Name: "bag"}.String())
+	assert.Equal(t, "2 (14 1/2 oz) cans", Package{Count: 2, Size: 14.5, Unit: "oz", Name: "can"}.String())
+	assert.Equal(t, "3 (4 ounce)", Package{Count: 3, Size: 4, Unit: "ounce"}.String())
+}
@@ -0,0 +1,536 @@
+package ingredients
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	json "github.com/goccy/go-json"
+	"github.com/jinzhu/inflection"
+	log "github.com/schollz/logger"
+)
+
+// Logger receives the trace messages of a Parser. A *Logger from
+// github.com/schollz/logger satisfies it.
+type Logger interface {
+	Tracef(format string, v ...interface{})
+}
+
+// packageLogger logs through the package-level logger, so that
+// log.SetLevel keeps working for the default Parser
+type packageLogger struct{}
+
+func (packageLogger) Tracef(format string, v ...interface{}) {
+	log.Tracef(format, v...)
+}
+
+// Corpus is the vocabulary that a Parser recognizes. Entries are words or
+// phrases like "brown sugar".
+type Corpus struct {
+	Ingredients []string
+	// Measures maps how a measure is written to its unit, e.g. "tbsp" to
+	// "tbl". The units are tsp, tbl, cup, pint, quart, gallon, milliliter,
+	// liter, can, ounce, gram, pound and kilogram, or "" for whole items.
+	Measures map[string]string
+	Numbers  []string
+	// Densities are in grams per cup
+	Densities map[string]float64
+	// Herbs, fruits and vegetables can be converted to cups without a measure
+	Herbs      []string
+	Fruits     []string
+	Vegetables []string
+	// Proteins, spices, dairy, grains, sweeteners, fats and liquids, with
+	// the lists above, give the Category of an ingredient
+	Proteins   []string
+	Spices     []string
+	Dairy      []string
+	Grains     []string
+	Sweeteners []string
+	Fats       []string
+	Liquids    []string
+	// Synonyms maps an ingredient to its canonical name, which is its ID,
+	// e.g. "courgette" to "zucchini"
+	Synonyms map[string]string
+	// Parents maps an ingredient ID to a more general one, e.g. "aged
+	// cheddar" to "cheddar", "cheddar" to "cheese" and "cheese" to "dairy"
+	Parents map[string]string
+	// Preparations are the ways an ingredient is prepared, e.g. "chopped",
+	// "melted" or "room temperature"
+	Preparations []string
+}
+
+// DefaultCorpus returns a copy of the built-in corpus
+func DefaultCorpus() Corpus {
+	return Corpus{
+		Ingredients:  normalizeWords(corpusIngredients),
+		Measures:     copyMap(corpusMeasuresMap),
+		Numbers:      normalizeWords(corpusNumbers),
+		Densities:    copyMap(densities),
+		Herbs:        sortedKeys(herbMap),
+		Fruits:       sortedKeys(fruitMap),
+		Vegetables:   sortedKeys(vegetableMap),
+		Proteins:     sortedKeys(proteinMap),
+		Spices:       sortedKeys(spiceMap),
+		Dairy:        sortedKeys(dairyMap),
+		Grains:       sortedKeys(grainMap),
+		Sweeteners:   sortedKeys(sweetenerMap),
+		Fats:         sortedKeys(fatMap),
+		Liquids:      sortedKeys(liquidMap),
+		Synonyms:     copyMap(corpusSynonyms),
+		Parents:      copyMap(corpusParents),
+		Preparations: sortedKeys(preparationMap),
+	}
+}
+
+// Add returns the corpus with the entries of another one. Like the corpus
+// generator, it adds the plurals of ingredients and measures, makes the
+// names in the classes and synonyms ingredients too and adds measures with
+// a trailing period.
+func (c Corpus) Add(extra Corpus) Corpus {
+	var ingredients []string
+	synonyms := make([]string, 0, 2*len(extra.Synonyms))
+	for k, v := range extra.Synonyms {
+		synonyms = append(synonyms, k, v)
+	}
+	sort.Strings(synonyms)
+	for _, list := range append(extra.classes(), extra.Ingredients, synonyms) {
+		for _, word := range normalizeWords(list) {
+			ingredients = append(ingredients, word, inflection.Plural(word))
+		}
+	}
+	sum := Corpus{
+		Ingredients:  union(c.Ingredients, ingredients),
+		Measures:     make(map[string]string, len(c.Measures)+2*len(extra.Measures)),
+		Numbers:      union(c.Numbers, normalizeWords(extra.Numbers)),
+		Densities:    make(map[string]float64, len(c.Densities)+len(extra.Densities)),
+		Herbs:        union(c.Herbs, normalizeWords(extra.Herbs)),
+		Fruits:       union(c.Fruits, normalizeWords(extra.Fruits)),
+		Vegetables:   union(c.Vegetables, normalizeWords(extra.Vegetables)),
+		Proteins:     union(c.Proteins, normalizeWords(extra.Proteins)),
+		Spices:       union(c.Spices, normalizeWords(extra.Spices)),
+		Dairy:        union(c.Dairy, normalizeWords(extra.Dairy)),
+		Grains:       union(c.Grains, normalizeWords(extra.Grains)),
+		Sweeteners:   union(c.Sweeteners, normalizeWords(extra.Sweeteners)),
+		Fats:         union(c.Fats, normalizeWords(extra.Fats)),
+		Liquids:      union(c.Liquids, normalizeWords(extra.Liquids)),
+		Synonyms:     copyMap(c.Synonyms),
+		Parents:      copyMap(c.Parents),
+		Preparations: union(c.Preparations, normalizeWords(extra.Preparations)),
+	}
+	for k, v := range c.Measures {
+		sum.Measures[k] = v
+	}
+	for k, v := range extra.Measures {
+		k = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(k)), ".")
+		for _, measure := range []string{k, inflection.Plural(k)} {
+			sum.Measures[measure] = v
+			sum.Measures[measure+"."] = v
+		}
+	}
+	for k, v := range c.Densities {
+		sum.Densities[k] = v
+	}
+	for k, v := range extra.Densities {
+		sum.Densities[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+	for k, v := range extra.Synonyms {
+		sum.Synonyms[strings.ToLower(strings.TrimSpace(k))] = strings.ToLower(strings.TrimSpace(v))
+	}
+	for k, v := range extra.Parents {
+		sum.Parents[strings.ToLower(strings.TrimSpace(k))] = strings.ToLower(strings.TrimSpace(v))
+	}
+	return sum
+}
+
+// classes returns the lists of names that belong to a class
+func (c Corpus) classes() [][]string {
+	return [][]string{c.Herbs, c.Fruits, c.Vegetables, c.Proteins, c.Spices, c.Dairy, c.Grains, c.Sweeteners, c.Fats, c.Liquids}
+}
+
+// LoadCorpus reads a corpus from a directory laid out like the corpus
+// directory of this repository: ingredients.txt and numbers.txt with one
+// entry per line, herbs.json, fruits.json, vegetables.json, proteins.json,
+// spices.json, dairy.json, grains.json, sweeteners.json, fats.json,
+// liquids.json and preparations.json with a list of names, densities.json
+// with grams per cup of each ingredient, synonyms.json with the canonical
+// name of each ingredient, parents.json with the parent of each ingredient
+// ID and measures.json with the unit of each way to write a measure.
+// Missing files are skipped. Add the result to DefaultCorpus to extend it.
+func LoadCorpus(dir string) (c Corpus, err error) {
+	for _, file := range []struct {
+		name string
+		into interface{}
+	}{
+		{"ingredients.txt", &c.Ingredients},
+		{"numbers.txt", &c.Numbers},
+		{"herbs.json", &c.Herbs},
+		{"fruits.json", &c.Fruits},
+		{"vegetables.json", &c.Vegetables},
+		{"proteins.json", &c.Proteins},
+		{"spices.json", &c.Spices},
+		{"dairy.json", &c.Dairy},
+		{"grains.json", &c.Grains},
+		{"sweeteners.json", &c.Sweeteners},
+		{"fats.json", &c.Fats},
+		{"liquids.json", &c.Liquids},
+		{"densities.json", &c.Densities},
+		{"synonyms.json", &c.Synonyms},
+		{"parents.json", &c.Parents},
+		{"preparations.json", &c.Preparations},
+		{"measures.json", &c.Measures},
+	} {
+		b, errRead := os.ReadFile(filepath.Join(dir, file.name))
+		if errors.Is(errRead, fs.ErrNotExist) {
+			continue
+		} else if errRead != nil {
+			err = fmt.Errorf("could not read corpus: %w", errRead)
+			return
+		}
+		if lines, ok := file.into.(*[]string); ok && filepath.Ext(file.name) == ".txt" {
+			*lines = normalizeWords(strings.Split(string(b), "\n"))
+		} else if errJSON := json.Unmarshal(b, file.into); errJSON != nil {
+			err = fmt.Errorf("could not parse %s: %w", file.name, errJSON)
+			return
+		}
+	}
+	for measure, unit := range c.Measures {
+		_, isVolume := conversionToCup[unit]
+		_, isWeight := gramConversions[unit]
+		if !isVolume && !isWeight && unit != "" {
+			err = fmt.Errorf("%w '%s' for '%s'", ErrUnknownUnit, unit, measure)
+			return
+		}
+	}
+	return
+}
+
+// inflectionRule singularizes the words that match a pattern
+type inflectionRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Parser extracts recipes with its own corpus and settings. Once built it
+// does not change, so it is safe to share between goroutines, and parsers
+// with different settings can be used side by side.
+type Parser struct {
+	corpus          Corpus
+	ingredientsTrie *Trie
+	measuresTrie    *Trie
+	numbersTrie     *Trie
+	// preparationsTrie finds the preparations and the descriptors of size
+	// and use, like "large" and "optional"
+	preparationsTrie *Trie
+	herbs            map[string]struct{}
+	fruits           map[string]struct{}
+	vegetables       map[string]struct{}
+	densitiesByID    map[string]float64
+	categories       map[Category]map[string]struct{}
+
+	singulars    []inflectionRule
+	uncountables map[string]bool
+
+	fuzzyThreshold float64
+	fuzzyTree      *bkTree
+
+	maxLineLength       int
+	maxSchemaLineLength int
+	minIngredients      int
+
+	client      *http.Client
+	headers     http.Header
+	maxBodySize int64
+	retry       RetryPolicy
+	limiter     *hostLimiter
+	robots      *RobotsCache
+	logger      Logger
+}
+
+// Option configures a Parser
+type Option func(*Parser)
+
+// defaultParser is used by the package-level functions
+var defaultParser = NewParser()
+
+// NewParser builds a Parser. Without options it behaves like the
+// package-level functions.
+func NewParser(options ...Option) *Parser {
+	p := &Parser{
+		corpus:              DefaultCorpus(),
+		uncountables:        make(map[string]bool),
+		maxLineLength:       150,
+		maxSchemaLineLength: 250,
+		minIngredients:      2,
+		client:              &http.Client{},
+		headers:             defaultHeaders(),
+		maxBodySize:         DefaultMaxBodySize,
+		logger:              packageLogger{},
+	}
+	WithSingular("(clove)(s)?$", "${1}")(p)
+	WithSingular("(potato)(es)?$", "${1}")(p)
+	WithSingular("(tomato)(es)?$", "${1}")(p)
+	WithUncountable("molasses")(p)
+	WithUncountable("bacon")(p)
+	for _, option := range options {
+		option(p)
+	}
+	p.buildTries()
+	return p
+}
+
+// WithCorpus replaces the words that the Parser recognizes. As with
+// Corpus.Add, plurals and measures with a trailing period are added.
+func WithCorpus(corpus Corpus) Option {
+	return func(p *Parser) {
+		p.corpus = Corpus{}.Add(corpus)
+	}
+}
+
+// WithCorpusAdditions adds to the words that the Parser recognizes, e.g.
+// regional ingredients loaded with LoadCorpus
+func WithCorpusAdditions(extra Corpus) Option {
+	return func(p *Parser) {
+		p.corpus = p.corpus.Add(extra)
+	}
+}
+
+// WithSingular adds an inflection rule for singularizing ingredient names,
+// e.g. WithSingular("(potato)(es)?$", "${1}"). Later rules take precedence.
+func WithSingular(pattern, replacement string) Option {
+	return func(p *Parser) {
+		p.singulars = append(p.singulars, inflectionRule{
+			re:          regexp.MustCompile("(?i)" + pattern),
+			replacement: replacement,
+		})
+	}
+}
+
+// WithUncountable keeps an ingredient name like "molasses" as it is
+func WithUncountable(word string) Option {
+	return func(p *Parser) {
+		p.uncountables[strings.ToLower(word)] = true
+	}
+}
+
+// WithFuzzyMatching corrects misspelled ingredients like "parmesean" when
+// they are at least as similar to an ingredient of the corpus as the
+// threshold, from 0 to 1. A threshold around 0.8 allows one typo in five
+// letters. The corrections are in LineInfo.Fuzzy.
+func WithFuzzyMatching(threshold float64) Option {
+	return func(p *Parser) {
+		p.fuzzyThreshold = threshold
+	}
+}
+
+// WithMaxLineLength sets the longest ingredient line that is parsed, and
+// the longest for lines from schema.org, which tend to be verbose
+func WithMaxLineLength(length, schemaOrgLength int) Option {
+	return func(p *Parser) {
+		p.maxLineLength = length
+		p.maxSchemaLineLength = schemaOrgLength
+	}
+}
+
+// WithMinIngredients sets how many ingredients a web page needs to be a recipe
+func WithMinIngredients(n int) Option {
+	return func(p *Parser) {
+		p.minIngredients = n
+	}
+}
+
+// WithHTTPClient sets the client that fetches recipes from urls
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Parser) {
+		p.client = client
+	}
+}
+
+// WithHeader sets a header of the requests that fetch recipes, e.g.
+// WithHeader("User-Agent", "my-app/1.0"). An empty value removes it.
+func WithHeader(key, value string) Option {
+	return func(p *Parser) {
+		if value == "" {
+			p.headers.Del(key)
+			return
+		}
+		p.headers.Set(key, value)
+	}
+}
+
+// WithMaxBodySize sets the largest page in bytes that is fetched
+func WithMaxBodySize(n int64) Option {
+	return func(p *Parser) {
+		p.maxBodySize = n
+	}
+}
+
+// WithRetries retries fetches that fail with a network error or a status
+// like 429 or 503. Zero delays are those of DefaultRetryPolicy.
+func WithRetries(policy RetryPolicy) Option {
+	return func(p *Parser) {
+		if policy.BaseDelay <= 0 {
+			policy.BaseDelay = DefaultRetryPolicy.BaseDelay
+		}
+		if policy.MaxDelay <= 0 {
+			policy.MaxDelay = DefaultRetryPolicy.MaxDelay
+		}
+		p.retry = policy
+	}
+}
+
+// WithRateLimit limits the requests to each host to perSecond, after a
+// burst of requests. The limit is shared by all the goroutines that use
+// the Parser. A rate of 0 removes the limit.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(p *Parser) {
+		p.limiter = nil
+		if perSecond > 0 {
+			p.limiter = newHostLimiter(perSecond, burst)
+		}
+	}
+}
+
+// WithRobots fetches the robots.txt of each site, and obeys it for the
+// User-Agent of the Parser, including its Crawl-delay. The cache can be
+// shared with other Parsers, and a new one is made if it is nil.
+func WithRobots(cache *RobotsCache) Option {
+	return func(p *Parser) {
+		if cache == nil {
+			cache = NewRobotsCache(0)
+		}
+		p.robots = cache
+	}
+}
+
+// WithLogger sets where the Parser sends its trace messages
+func WithLogger(logger Logger) Option {
+	return func(p *Parser) {
+		p.logger = logger
+	}
+}
+
+// buildTries builds the tries for fast pattern matching from the corpus
+func (p *Parser) buildTries() {
+	p.ingredientsTrie = newTrie(p.corpus.Ingredients)
+	p.measuresTrie = newTrie(sortedKeys(p.corpus.Measures))
+	p.numbersTrie = newTrie(p.corpus.Numbers)
+	p.preparationsTrie = newTrie(append(sortedKeys(descriptors), p.corpus.Preparations...))
+	p.fuzzyTree = nil
+	if p.fuzzyThreshold > 0 && p.fuzzyThreshold <= 1 {
+		p.fuzzyTree = newBKTree(p.corpus.Ingredients)
+	}
+	p.herbs = p.toIDSet(p.corpus.Herbs)
+	p.fruits = p.toIDSet(p.corpus.Fruits)
+	p.vegetables = p.toIDSet(p.corpus.Vegetables)
+	p.categories = map[Category]map[string]struct{}{
+		CategoryHerbSpice: p.toIDSet(union(p.corpus.Herbs, p.corpus.Spices)),
+		CategoryFruit:     p.fruits,
+		CategoryVegetable: p.vegetables,
+		CategoryProtein:   p.toIDSet(p.corpus.Proteins),
+		CategoryDairy:     p.toIDSet(p.corpus.Dairy),
+		CategoryGrain:     p.toIDSet(p.corpus.Grains),
+		CategorySweetener: p.toIDSet(p.corpus.Sweeteners),
+		CategoryFat:       p.toIDSet(p.corpus.Fats),
+		CategoryLiquid:    p.toIDSet(p.corpus.Liquids),
+	}
+	p.densitiesByID = make(map[string]float64, len(p.corpus.Densities))
+	for _, name := range sortedKeys(p.corpus.Densities) {
+		// an ingredient named like its ID takes precedence over its synonyms
+		id := p.canonical(p.singular(name))
+		if _, ok := p.densitiesByID[id]; !ok || name == id {
+			p.densitiesByID[id] = p.corpus.Densities[name]
+		}
+	}
+}
+
+// singular returns the singular of an ingredient name
+func (p *Parser) singular(word string) string {
+	if p.uncountables[strings.ToLower(word)] {
+		return word
+	}
+	for i := len(p.singulars) - 1; i >= 0; i-- {
+		if p.singulars[i].re.MatchString(word) {
+			return p.singulars[i].re.ReplaceAllString(word, p.singulars[i].replacement)
+		}
+	}
+	return inflection.Singular(word)
+}
+
+// plural returns the plural of an ingredient name or a unit
+func (p *Parser) plural(word string) string {
+	if p.uncountables[strings.ToLower(word)] {
+		return word
+	}
+	return inflection.Plural(word)
+}
+
+// normalizeWords trims the space padding of the corpus entries and
+// lowercases them, since lines are lowercased before matching
+func normalizeWords(words []string) (normalized []string) {
+	normalized = make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			normalized = append(normalized, word)
+		}
+	}
+	return
+}
+
+// union returns the words of both lists without duplicates
+func union(a, b []string) (words []string) {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	words = make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, word := range list {
+			if _, ok := seen[word]; !ok {
+				seen[word] = struct{}{}
+				words = append(words, word)
+			}
+		}
+	}
+	return
+}
+
+// toSet returns a set of the words
+func toSet(words []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+	return set
+}
+
+// toIDSet returns the set of the words and of their IDs, so that the
+// synonyms of a word are found by their ID
+func (p *Parser) toIDSet(words []string) map[string]struct{} {
+	set := toSet(words)
+	for _, word := range words {
+		set[p.canonical(word)] = struct{}{}
+	}
+	return set
+}
+
+// copyMap returns a copy of a map
+func copyMap[V any](m map[string]V) map[string]V {
+	copied := make(map[string]V, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) (keys []string) {
+	keys = make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
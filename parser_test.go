@@ -0,0 +1,170 @@
+package ingredients
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserDefault(t *testing.T) {
+	p := NewParser()
+	text := "1 cup flour\n2 eggs\n3 cloves garlic"
+	fromParser, err := p.ParseTextIngredients(text)
+	assert.Nil(t, err)
+	fromPackage, err := ParseTextIngredients(text)
+	assert.Nil(t, err)
+	assert.Equal(t, fromPackage.String(), fromParser.String())
+}
+
+func TestParserMinIngredients(t *testing.T) {
+	htmlS := `<html><body><ul><li>1 cup flour</li><li>2 eggs</li></ul></body></html>`
+	_, err := NewParser(WithMinIngredients(3)).NewFromHTML("test", htmlS)
+	assert.NotNil(t, err)
+	r, err := NewParser(WithMinIngredients(1)).NewFromHTML("test", htmlS)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(r.Ingredients))
+}
+
+func TestParserMaxLineLength(t *testing.T) {
+	text := "1 cup flour, sifted twice over a large bowl\n2 eggs"
+	ingredientList, err := NewParser(WithMaxLineLength(20, 20)).ParseTextIngredients(text)
+	assert.Nil(t, err)
+	assert.Equal(t, "2 whole eggs\n", ingredientList.String())
+}
+
+func TestParserCorpus(t *testing.T) {
+	corpus := DefaultCorpus()
+	corpus.Ingredients = append(corpus.Ingredients, "wattleseed")
+	p := NewParser(WithCorpus(corpus))
+
+	ingredientList, err := p.ParseTextIngredients("1 cup wattleseed\n2 eggs")
+	assert.Nil(t, err)
+	assert.Equal(t, "1 cup wattleseed\n2 whole eggs\n", ingredientList.String())
+
+	// the default parser is not changed
+	ingredientList, err = ParseTextIngredients("1 cup wattleseed\n2 eggs")
+	assert.Nil(t, err)
+	assert.Equal(t, "2 whole eggs\n", ingredientList.String())
+}
+
+func TestParserSingular(t *testing.T) {
+	p := NewParser(WithSingular("(anchov)(ies)?$", "${1}y"), WithUncountable("hummus"))
+	assert.Equal(t, "anchovy", p.singular("anchovies"))
+	assert.Equal(t, "hummus", p.singular("hummus"))
+	assert.Equal(t, "clove", p.singular("cloves"))
+	assert.Equal(t, "potato", p.singular("potatoes"))
+	assert.Equal(t, "molasses", p.singular("molasses"))
+}
+
+func TestParserPlural(t *testing.T) {
+	ingredientList, err := ParseTextIngredients("2 strips bacon\n3 eggs")
+	assert.Nil(t, err)
+	assert.Equal(t, "2 whole bacon\n3 whole eggs\n", ingredientList.String())
+
+	p := NewParser(WithUncountable("egg"))
+	ingredientList, err = p.ParseTextIngredients("2 strips bacon\n3 eggs")
+	assert.Nil(t, err)
+	assert.Equal(t, "2 whole bacon\n3 whole egg\n", ingredientList.String())
+	assert.Equal(t, "egg", p.pluralUnit("egg", 3))
+	assert.Equal(t, "cups", p.pluralUnit("cup", 2))
+}
+
+type captureLogger []string
+
+func (l *captureLogger) Tracef(format string, v ...interface{}) {
+	*l = append(*l, fmt.Sprintf(format, v...))
+}
+
+func TestParserLogger(t *testing.T) {
+	logger := &captureLogger{}
+	_, err := NewParser(WithLogger(logger)).ParseTextIngredients("1 cup flour\nthe best cookies ever")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, *logger)
+}
+
+func TestParserHTTPClient(t *testing.T) {
+	b, err := os.ReadFile("testing/sites/joyfoodsunshine.com/the-most-amazing-chocolate-chip-cookies/index.html")
+	assert.Nil(t, err)
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userAgent = req.Header.Get("User-Agent")
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Transport = userAgentTransport{"test-agent", client.Transport}
+	r, err := NewParser(WithHTTPClient(client)).NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-agent", userAgent)
+	assert.NotEmpty(t, r.Ingredients)
+}
+
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
+
+func TestCorpusAdd(t *testing.T) {
+	p := NewParser(WithCorpusAdditions(Corpus{
+		Ingredients: []string{"Wattleseed"},
+		Measures:    map[string]string{"glass": "cup"},
+		Densities:   map[string]float64{"wattleseed": 120},
+		Herbs:       []string{"lemon myrtle"},
+	}))
+
+	r, err := p.NewFromHTML("test", `<ul><li>2 glasses wattleseeds</li><li>1 glass. milk</li><li>4 lemon myrtle</li></ul>`)
+	assert.Nil(t, err)
+	assert.Equal(t, "glasses wattleseed", r.Lines[0].Ingredient.Measure.Name+" "+r.Lines[0].Ingredient.Name)
+	assert.Equal(t, 2.0, r.Lines[0].Ingredient.Measure.Cups)
+	assert.Equal(t, 240.0, r.Lines[0].Ingredient.Measure.Weight)
+	assert.Equal(t, WeightDensity, r.Lines[0].Ingredient.Measure.WeightSource)
+	assert.Equal(t, 1.0, r.Lines[1].Ingredient.Measure.Cups)
+	assert.InDelta(t, 4*0.0208333, r.Lines[2].Ingredient.Measure.Cups, 1e-6)
+
+	// the default corpus is not changed
+	_, ok := DefaultCorpus().Measures["glass"]
+	assert.False(t, ok)
+}
+
+func TestLoadCorpus(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ingredients.txt": "wattleseed\nQuandong\n",
+		"measures.json":   `{"glass": "cup"}`,
+		"densities.json":  `{"wattleseed": 120}`,
+		"fruits.json":     `["davidson plum"]`,
+	}
+	for name, content := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	c, err := LoadCorpus(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"wattleseed", "quandong"}, c.Ingredients)
+	assert.Equal(t, map[string]string{"glass": "cup"}, c.Measures)
+	assert.Equal(t, []string{"davidson plum"}, c.Fruits)
+
+	ingredientList, err := NewParser(WithCorpusAdditions(c)).ParseTextIngredients("1 glass quandongs\n2 davidson plums")
+	assert.Nil(t, err)
+	assert.Equal(t, "1 glass quandong\n2 whole davidson plums\n", ingredientList.String())
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "measures.json"), []byte(`{"glass": "beaker"}`), 0644))
+	_, err = LoadCorpus(dir)
+	assert.NotNil(t, err)
+
+	// the corpus directory of this repository loads too
+	c, err = LoadCorpus("corpus")
+	assert.Nil(t, err)
+	assert.Contains(t, c.Herbs, "basil")
+}
@@ -0,0 +1,138 @@
+package ingredients
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// descriptor is what a word that is not a preparation says about an
+// ingredient
+type descriptor int
+
+const (
+	descriptorSize descriptor = iota
+	descriptorOptional
+	descriptorToTaste
+	descriptorDivided
+)
+
+// descriptors are the words for the size of an ingredient and for how it
+// is used in the recipe
+var descriptors = map[string]descriptor{
+	"extra large": descriptorSize,
+	"jumbo":       descriptorSize,
+	"large":       descriptorSize,
+	"medium":      descriptorSize,
+	"small":       descriptorSize,
+	"optional":    descriptorOptional,
+	"if desired":  descriptorOptional,
+	"to taste":    descriptorToTaste,
+	"divided":     descriptorDivided,
+}
+
+// getPreparation reads how the ingredient of a line is prepared, its size
+// and whether it is optional, to taste or divided, from the words around
+// it and the notes in parentheses. An adverb stays with its preparation,
+// as in "finely chopped", and words that are part of the ingredient, like
+// the "whipped" of "whipped cream", are left alone.
+func (lineInfo *LineInfo) getPreparation(p *Parser, notes []string) {
+	ingredientStart, ingredientEnd := -1, -1
+	if len(lineInfo.IngredientsInString) > 0 {
+		ingredient := lineInfo.IngredientsInString[0]
+		ingredientStart = ingredient.Position + 1
+		ingredientEnd = ingredient.Position + utf8.RuneCountInString(ingredient.Word)
+	}
+	lineInfo.describe(p, lineInfo.Line, ingredientStart, ingredientEnd)
+	for _, note := range notes {
+		lineInfo.describe(p, SanitizeLine(note), -1, -1)
+	}
+}
+
+// describe adds the preparations and descriptors in a text to the
+// ingredient, skipping the ones between two rune positions
+func (lineInfo *LineInfo) describe(p *Parser, text string, skipStart, skipEnd int) {
+	ingredient := &lineInfo.Ingredient
+	runes := []rune(text)
+	for _, match := range p.findPreparations(text) {
+		start := match.Position + 1
+		end := match.Position + utf8.RuneCountInString(match.Word)
+		if start <= skipEnd && skipStart <= end {
+			continue
+		}
+		kind, ok := descriptors[match.Word]
+		if !ok {
+			preparation := match.Word
+			if adverb := wordBefore(runes, match.Position); len(adverb) > 3 && strings.HasSuffix(adverb, "ly") {
+				preparation = adverb + " " + preparation
+			}
+			if !slices.Contains(ingredient.Preparation, preparation) {
+				ingredient.Preparation = append(ingredient.Preparation, preparation)
+			}
+			continue
+		}
+		switch kind {
+		case descriptorSize:
+			if ingredient.Size == "" {
+				ingredient.Size = match.Word
+			}
+		case descriptorOptional:
+			ingredient.Optional = true
+		case descriptorToTaste:
+			ingredient.ToTaste = true
+		case descriptorDivided:
+			ingredient.Divided = true
+		}
+	}
+}
+
+// toTaste reports whether a line says to season to taste, in its text or
+// in parentheses, which is why a line like "kosher salt, to taste" has no
+// amount
+func (p *Parser) toTaste(line string) bool {
+	line = strings.NewReplacer("(", " ", ")", " ").Replace(line)
+	for _, match := range p.findPreparations(SanitizeLine(line)) {
+		if kind, ok := descriptors[match.Word]; ok && kind == descriptorToTaste {
+			return true
+		}
+	}
+	return false
+}
+
+// findPreparations returns the preparations and descriptors in a text.
+// Matches of the trie do not share the space between them, so the text is
+// searched again with the words that were found blanked out, which finds
+// the "optional" of "melted optional".
+func (p *Parser) findPreparations(text string) (matches []WordPosition) {
+	runes := []rune(text)
+	for {
+		found := p.preparationsTrie.findAll(string(runes))
+		if len(found) == 0 {
+			break
+		}
+		for _, match := range found {
+			end := match.Position + utf8.RuneCountInString(match.Word)
+			for i := match.Position + 1; i <= end; i++ {
+				runes[i] = ' '
+			}
+		}
+		matches = append(matches, found...)
+	}
+	slices.SortFunc(matches, func(a, b WordPosition) int {
+		return a.Position - b.Position
+	})
+	return
+}
+
+// wordBefore returns the word that ends at the space at a position
+func wordBefore(runes []rune, position int) string {
+	end := min(position, len(runes))
+	if end < 0 {
+		return ""
+	}
+	start := end
+	for start > 0 && runes[start-1] != ' ' {
+		start--
+	}
+	return string(runes[start:end])
+}
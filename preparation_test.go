@@ -0,0 +1,93 @@
+package ingredients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreparation(t *testing.T) {
+	r := parseLines(t, `2 large eggs, lightly beaten
+1 cup finely chopped onion
+1/2 cup butter, melted and cooled
+1 cup sugar, divided
+1 tsp salt, to taste
+2 medium tomatoes, diced (optional)
+1 cup whipped cream
+1 cup packed brown sugar
+2 tablespoons butter, melted, optional
+1 cup onion, chopped, divided
+3 small potatoes, peeled, diced`)
+	tests := []struct {
+		name        string
+		preparation []string
+		size        string
+		optional    bool
+		toTaste     bool
+		divided     bool
+	}{
+		{"egg", []string{"lightly beaten"}, "large", false, false, false},
+		{"onion", []string{"finely chopped"}, "", false, false, false},
+		{"butter", []string{"melted", "cooled"}, "", false, false, false},
+		{"sugar", nil, "", false, false, true},
+		{"salt", nil, "", false, true, false},
+		{"tomato", []string{"diced"}, "medium", true, false, false},
+		// the preparation is part of the ingredient
+		{"whipped cream", nil, "", false, false, false},
+		{"brown sugar", []string{"packed"}, "", false, false, false},
+		// descriptors next to each other are all found
+		{"butter", []string{"melted"}, "", true, false, false},
+		{"onion", []string{"chopped"}, "", false, false, true},
+		{"potato", []string{"peeled", "diced"}, "small", false, false, false},
+	}
+	assert.Equal(t, len(tests), len(r.Lines))
+	for i, test := range tests {
+		ing := r.Lines[i].Ingredient
+		assert.Equal(t, test.name, ing.Name)
+		assert.Equal(t, test.preparation, ing.Preparation, test.name)
+		assert.Equal(t, test.size, ing.Size, test.name)
+		assert.Equal(t, test.optional, ing.Optional, test.name)
+		assert.Equal(t, test.toTaste, ing.ToTaste, test.name)
+		assert.Equal(t, test.divided, ing.Divided, test.name)
+	}
+}
+
+func TestPreparationConsolidate(t *testing.T) {
+	r := parseLines(t, `1 cup butter, softened
+2 tablespoons butter, melted
+1 teaspoon vanilla (optional)
+1/2 teaspoon vanilla, divided
+1 teaspoon vanilla (optional)`)
+	assert.Equal(t, 2, len(r.Ingredients))
+	assert.Equal(t, []string{"softened", "melted"}, r.Ingredients[0].Preparation)
+	// an ingredient that is needed once is not optional
+	assert.False(t, r.Ingredients[1].Optional)
+	assert.True(t, r.Ingredients[1].Divided)
+}
+
+func TestPreparationCorpus(t *testing.T) {
+	lines := "1 cup spinach, wilted\n1 cup butter"
+	ingredientList, err := ParseTextIngredients(lines)
+	assert.Nil(t, err)
+	assert.Nil(t, ingredientList.Ingredients[0].Preparation)
+
+	p := NewParser(WithCorpusAdditions(Corpus{Preparations: []string{"Wilted"}}))
+	ingredientList, err = p.ParseTextIngredients(lines)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"wilted"}, ingredientList.Ingredients[0].Preparation)
+}
+
+func TestPreparationToTasteWithoutAmount(t *testing.T) {
+	ingredientList, err := ParseTextIngredients("kosher salt, to taste\n1 cup flour\nblack pepper (to taste)\nsalt")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(ingredientList.Ingredients))
+	for _, i := range []int{0, 2} {
+		ing := ingredientList.Ingredients[i]
+		assert.True(t, ing.ToTaste, ing.Name)
+		assert.Equal(t, 0.0, ing.Measure.Amount, ing.Name)
+	}
+	assert.Equal(t, "salt", ingredientList.Ingredients[0].Name)
+	assert.Equal(t, "black pepper", ingredientList.Ingredients[2].Name)
+	// a line without an amount that is not to taste is still left out
+	assert.Equal(t, "flour", ingredientList.Ingredients[1].Name)
+}
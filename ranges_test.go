@@ -0,0 +1,63 @@
+package ingredients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRanges(t *testing.T) {
+	tests := []struct {
+		input       string
+		amount      float64
+		min         float64
+		max         float64
+		approximate bool
+	}{
+		{"2-3 tablespoons olive oil", 3, 2, 3, false},
+		{"1 to 2 tablespoons butter", 2, 1, 2, false},
+		{"1 or 2 cups milk", 2, 1, 2, false},
+		{"1 - 1 1/2 cups flour", 1.5, 1, 1.5, false},
+		{"1-1/2 cups sugar", 1.5, 0, 0, false},
+		{"2 1/2 cups flour", 2.5, 0, 0, false},
+		{"about 2 cups flour", 2, 0, 0, true},
+		{"~1 cup milk", 1, 0, 0, true},
+		{"roughly 2-3 cups spinach", 3, 2, 3, true},
+		{"24-30 ounces chicken", 30, 24, 30, false},
+	}
+	for _, test := range tests {
+		r := &Recipe{FileName: "lines", FileContent: test.input}
+		_, lineInfo := scoreLine(test.input)
+		r.Lines = []LineInfo{lineInfo}
+		assert.Nil(t, r.parseRecipe(false))
+		if !assert.Equal(t, 1, len(r.Lines), test.input) {
+			continue
+		}
+		m := r.Lines[0].Ingredient.Measure
+		assert.Equal(t, test.amount, m.Amount, test.input)
+		assert.Equal(t, test.min, m.Min, test.input)
+		assert.Equal(t, test.max, m.Max, test.input)
+		assert.Equal(t, test.approximate, m.Approximate, test.input)
+	}
+}
+
+func TestRangesString(t *testing.T) {
+	ingredientList, err := ParseTextIngredients(`2-3 tablespoons olive oil
+about 2 cups flour
+1 to 2 whole eggs`)
+	assert.Nil(t, err)
+	assert.Equal(t, `2–3 tablespoons olive oil
+about 2 cups flour
+1–2 whole eggs`, strings.TrimSpace(ingredientList.String()))
+}
+
+func TestRangesConsolidate(t *testing.T) {
+	r := &Recipe{FileName: "lines", FileContent: "1 to 2 tablespoons butter\n1 tablespoon butter"}
+	_, r.Lines = scoreLines([]string{"1 to 2 tablespoons butter", "1 tablespoons butter"})
+	assert.Nil(t, r.parseRecipe(false))
+	assert.Equal(t, 1, len(r.Ingredients))
+	assert.Equal(t, 3.0, r.Ingredients[0].Measure.Amount)
+	assert.Equal(t, 2.0, r.Ingredients[0].Measure.Min)
+	assert.Equal(t, 3.0, r.Ingredients[0].Measure.Max)
+}
@@ -0,0 +1,80 @@
+package ingredients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostLimiter(t *testing.T) {
+	l := newHostLimiter(20, 2)
+	ctx := context.Background()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, l.wait(ctx, "a.example"))
+		}()
+	}
+	wg.Wait()
+	// a burst of 2, then 4 at 20 a second
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+
+	// other hosts have their own bucket
+	start = time.Now()
+	assert.Nil(t, l.wait(ctx, "b.example"))
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestHostLimiterCancel(t *testing.T) {
+	l := newHostLimiter(1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, l.wait(ctx, "a.example"))
+	err := l.wait(ctx, "a.example")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	// the token is given back
+	assert.InDelta(t, 0, l.buckets["a.example"].tokens, 0.1)
+}
+
+func TestRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.Write([]byte("<ul><li>1 cup flour</li><li>2 eggs</li></ul>"))
+	}))
+	defer server.Close()
+
+	p := NewParser(WithRateLimit(10, 1))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.NewFromURL(server.URL)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 4, len(times))
+	assert.GreaterOrEqual(t, times[3].Sub(times[0]), 290*time.Millisecond)
+
+	// the same server under another host name is not limited with it
+	other := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	start := time.Now()
+	_, err := p.NewFromURL(other)
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(start), 90*time.Millisecond)
+}
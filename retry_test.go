@@ -0,0 +1,163 @@
+package ingredients
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyServer fails with a status until it has been asked a number of times
+func flakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	b, err := os.ReadFile(fetchTestPage)
+	assert.Nil(t, err)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if requests.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write(b)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestRetries(t *testing.T) {
+	fast := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	server, requests := flakyServer(t, 2, http.StatusServiceUnavailable, "")
+	r, err := NewParser(WithRetries(fast)).NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, r.Ingredients)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// no retries by default
+	server, requests = flakyServer(t, 2, http.StatusServiceUnavailable, "")
+	_, err = NewFromURL(server.URL)
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, int32(1), requests.Load())
+
+	// too many failures
+	server, requests = flakyServer(t, 10, http.StatusBadGateway, "")
+	_, err = NewParser(WithRetries(fast)).NewFromURL(server.URL)
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+	assert.Equal(t, int32(4), requests.Load())
+
+	// a page that is not there will not be
+	server, requests = flakyServer(t, 10, http.StatusNotFound, "")
+	_, err = NewParser(WithRetries(fast)).NewFromURL(server.URL)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	fast := RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	// a refused connection may be accepted later
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	logger := &captureLogger{}
+	_, err := NewParser(WithRetries(fast), WithLogger(logger)).NewFromURL(server.URL)
+	assert.NotNil(t, err)
+	assert.Equal(t, fast.MaxRetries, retries(*logger))
+
+	// a bad url fails after one attempt
+	for _, url := range []string{"ftp://example.com/recipe", "http://%41:8080/", "http://[::1"} {
+		logger = &captureLogger{}
+		_, err = NewParser(WithRetries(fast), WithLogger(logger)).NewFromURL(url)
+		var fetchErr *FetchError
+		assert.True(t, errors.As(err, &fetchErr), url)
+		assert.Equal(t, 0, retries(*logger), url)
+	}
+}
+
+// retries counts the retries in the trace messages of a Parser
+func retries(messages []string) (n int) {
+	for _, message := range messages {
+		if strings.HasPrefix(message, "retrying ") {
+			n++
+		}
+	}
+	return
+}
+
+func TestRetryable(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, retryable(ctx, &FetchError{Err: &StatusError{StatusCode: http.StatusServiceUnavailable}}))
+	assert.False(t, retryable(ctx, &FetchError{Err: &StatusError{StatusCode: http.StatusNotFound}}))
+	assert.True(t, retryable(ctx, &url.Error{Op: "Get", Err: syscall.ECONNRESET}))
+	assert.True(t, retryable(ctx, &url.Error{Op: "Get", Err: context.DeadlineExceeded}))
+	assert.False(t, retryable(ctx, &url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}))
+	assert.False(t, retryable(ctx, &url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}))
+	assert.False(t, retryable(ctx, &FetchError{Err: ErrBodyTooLarge}))
+	assert.False(t, retryable(ctx, errors.New("unsupported protocol scheme")))
+}
+
+func TestRetryAfter(t *testing.T) {
+	server, requests := flakyServer(t, 1, http.StatusTooManyRequests, "1")
+	p := NewParser(WithRetries(RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second}))
+	start := time.Now()
+	_, err := p.NewFromURL(server.URL)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// a wait longer than the context has left is not retried
+	server, requests = flakyServer(t, 1, http.StatusTooManyRequests, "5")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = p.NewFromURLWithContext(ctx, server.URL)
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, 5*time.Second, statusErr.RetryAfter)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestRetryCancel(t *testing.T) {
+	server, _ := flakyServer(t, 10, http.StatusInternalServerError, "")
+	p := NewParser(WithRetries(RetryPolicy{MaxRetries: 5, BaseDelay: time.Second}))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := p.NewFromURLWithContext(ctx, server.URL)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, backoff := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		backoff *= time.Millisecond
+		delay := policy.delay(attempt, errors.New("network"))
+		assert.GreaterOrEqual(t, delay, backoff/2)
+		assert.LessOrEqual(t, delay, backoff)
+	}
+	assert.Equal(t, 3*time.Second, policy.delay(0, &StatusError{RetryAfter: 3 * time.Second}))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Mon, 01 Jan 2024 12:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Mon, 01 Jan 2024 11:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+}
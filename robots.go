@@ -0,0 +1,355 @@
+package ingredients
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRobotsTTL is how long a robots.txt is kept by a RobotsCache
+const DefaultRobotsTTL = 24 * time.Hour
+
+// maxRobotsSize is the most of a robots.txt that is read, as in RFC 9309
+const maxRobotsSize = 500 << 10
+
+// robotsUnavailableTTL is how long a site whose robots.txt could not be
+// read because of the server is disallowed before it is asked again
+const robotsUnavailableTTL = time.Minute
+
+// RobotsCache keeps the robots.txt of each site, and when each site can
+// next be fetched according to its Crawl-delay. It can be shared by
+// Parsers, and by the goroutines that use them.
+type RobotsCache struct {
+	ttl            time.Duration
+	unavailableTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+	next    map[string]time.Time
+}
+
+// robotsEntry is a robots.txt that is being fetched until ready is closed
+type robotsEntry struct {
+	ready   chan struct{}
+	robots  *robots
+	expires time.Time
+}
+
+// NewRobotsCache returns a cache that keeps each robots.txt for a ttl,
+// DefaultRobotsTTL if it is zero
+func NewRobotsCache(ttl time.Duration) *RobotsCache {
+	if ttl <= 0 {
+		ttl = DefaultRobotsTTL
+	}
+	return &RobotsCache{
+		ttl:            ttl,
+		unavailableTTL: min(ttl, robotsUnavailableTTL),
+		entries:        make(map[string]*robotsEntry),
+		next:           make(map[string]time.Time),
+	}
+}
+
+// robots are the groups of rules of a robots.txt
+type robots struct {
+	groups []robotsGroup
+}
+
+// robotsGroup are the rules for some user agents
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// allowAll and disallowAll are the robots of sites without a robots.txt,
+// and of sites whose robots.txt is unavailable
+var (
+	allowAll    = &robots{}
+	disallowAll = &robots{groups: []robotsGroup{{agents: []string{"*"}, rules: []robotsRule{{pattern: "/"}}}}}
+)
+
+// check returns an error if the robots.txt of the site of a url disallows
+// it for the user agent of the Parser, or else the Crawl-delay of the site
+func (c *RobotsCache) check(ctx context.Context, p *Parser, rawURL string) (crawlDelay time.Duration, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		// the request fails on its own
+		return 0, nil
+	}
+	rules, err := c.get(ctx, p, u)
+	if err != nil {
+		return 0, &FetchError{URL: rawURL, Err: err}
+	}
+	userAgent := p.headers.Get("User-Agent")
+	group := rules.group(userAgent)
+	if !group.allowed(u.EscapedPath(), u.RawQuery) {
+		return 0, &FetchError{URL: rawURL, Err: &DisallowedError{URL: rawURL, UserAgent: userAgent}}
+	}
+	return group.crawlDelay, nil
+}
+
+// get returns the robots.txt of the site of a url, fetching it once for
+// all the goroutines that ask for it
+func (c *RobotsCache) get(ctx context.Context, p *Parser, u *url.URL) (*robots, error) {
+	site := u.Scheme + "://" + u.Host
+	c.mu.Lock()
+	e, ok := c.entries[site]
+	if ok {
+		select {
+		case <-e.ready:
+			if time.Now().After(e.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		e = &robotsEntry{ready: make(chan struct{})}
+		c.entries[site] = e
+		c.mu.Unlock()
+
+		rules, err := p.fetchRobots(ctx, site+"/robots.txt")
+		if err != nil {
+			// try again next time
+			c.mu.Lock()
+			delete(c.entries, site)
+			c.mu.Unlock()
+			rules = nil
+		}
+		e.robots = rules
+		e.expires = time.Now().Add(c.ttl)
+		if rules == disallowAll {
+			// the server may be back soon
+			e.expires = time.Now().Add(c.unavailableTTL)
+		}
+		close(e.ready)
+		return rules, err
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-e.ready:
+	}
+	if e.robots == nil {
+		// the fetch failed for the goroutine that made it
+		return c.get(ctx, p, u)
+	}
+	return e.robots, nil
+}
+
+// wait waits until a site can be fetched after its Crawl-delay
+func (c *RobotsCache) wait(ctx context.Context, host string, crawlDelay time.Duration) error {
+	if crawlDelay <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	now := time.Now()
+	at := c.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	c.next[host] = at.Add(crawlDelay)
+	c.mu.Unlock()
+	return sleep(ctx, at.Sub(now))
+}
+
+// fetchRobots fetches a robots.txt, retrying failures that may pass. A
+// missing robots.txt allows all, and one that cannot be read because of
+// the server or the network disallows all, which the cache keeps for a
+// short time.
+func (p *Parser) fetchRobots(ctx context.Context, robotsURL string) (rules *robots, err error) {
+	err = p.retrying(ctx, robotsURL, func() (err error) {
+		rules, err = p.fetchRobotsOnce(ctx, robotsURL)
+		return
+	})
+	if err != nil && ctx.Err() == nil {
+		p.logger.Tracef("could not fetch %s: %s", robotsURL, err)
+		return disallowAll, nil
+	}
+	return
+}
+
+// fetchRobotsOnce fetches a robots.txt, waiting for the rate limit of its
+// host. The statuses 429 and 5xx are errors, so that they can be retried.
+func (p *Parser) fetchRobotsOnce(ctx context.Context, robotsURL string) (*robots, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range p.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "text/plain,*/*;q=0.8")
+	if p.limiter != nil {
+		if err := p.limiter.wait(ctx, req.URL.Host); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	p.logger.Tracef("fetched %s: %s", robotsURL, resp.Status)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return parseRobots(io.LimitReader(resp.Body, maxRobotsSize)), nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	default:
+		return allowAll, nil
+	}
+}
+
+// parseRobots reads the groups of a robots.txt. Consecutive User-agent
+// lines start a group, which has the rules up to the next User-agent.
+func parseRobots(r io.Reader) *robots {
+	rules := &robots{}
+	var group *robotsGroup
+	inAgents := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				rules.groups = append(rules.groups, robotsGroup{})
+				group = &rules.groups[len(rules.groups)-1]
+				inAgents = true
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+			continue
+		case "allow", "disallow":
+			if group != nil && value != "" {
+				group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); group != nil && err == nil && seconds > 0 {
+				group.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+		inAgents = false
+	}
+	return rules
+}
+
+// group returns the rules for a user agent, from the groups for its
+// product token, or else from the groups for "*"
+func (r *robots) group(userAgent string) (group robotsGroup) {
+	token := productToken(userAgent)
+	best := "*"
+	for _, g := range r.groups {
+		if slices.Contains(g.agents, token) {
+			best = token
+		}
+	}
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if agent == best {
+				group.rules = append(group.rules, g.rules...)
+				if g.crawlDelay > group.crawlDelay {
+					group.crawlDelay = g.crawlDelay
+				}
+				break
+			}
+		}
+	}
+	return
+}
+
+// productToken returns the name of the crawler in a User-Agent, like the
+// "ingredients" of DefaultUserAgent, in lower case as in RFC 9309. It is
+// the first product with a version other than the "Mozilla" that browsers
+// and crawlers start with, or else the first word.
+func productToken(userAgent string) string {
+	fields := strings.FieldsFunc(strings.ToLower(userAgent), func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')' || r == ';'
+	})
+	for _, field := range fields {
+		name, _, versioned := strings.Cut(field, "/")
+		if versioned && name != "mozilla" && name != "" && strings.Trim(name, "abcdefghijklmnopqrstuvwxyz_-") == "" {
+			return name
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	name, _, _ := strings.Cut(fields[0], "/")
+	return name
+}
+
+// allowed returns whether a path can be fetched. The rule with the
+// longest pattern that matches wins, and Allow wins a tie.
+func (g robotsGroup) allowed(path, query string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	if query != "" {
+		path += "?" + query
+	}
+	allow, longest := true, -1
+	for _, rule := range g.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allow, longest = rule.allow, len(rule.pattern)
+		}
+	}
+	return allow
+}
+
+// robotsMatch returns whether a path starts with a pattern, where "*" is
+// any text and a trailing "$" is the end of the path
+func robotsMatch(pattern, path string) bool {
+	end := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !end || rest == ""
+	}
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	last := parts[len(parts)-1]
+	if end {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
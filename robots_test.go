@@ -0,0 +1,259 @@
+package ingredients
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRobots = `# robots for a recipe site
+User-agent: *
+Disallow: /private/
+Allow: /private/recipes/
+Disallow: /*.pdf$
+Disallow: /search?
+
+User-agent: BadBot
+User-agent: ingredients
+Disallow: /members
+Crawl-delay: 0.1
+
+User-agent: badbot
+Disallow: /
+`
+
+func TestParseRobots(t *testing.T) {
+	rules := parseRobots(strings.NewReader(testRobots))
+	assert.Equal(t, 3, len(rules.groups))
+	assert.Equal(t, []string{"badbot", "ingredients"}, rules.groups[1].agents)
+
+	everyone := rules.group("SomeBrowser/1.0")
+	tests := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/", true},
+		{"/recipes/cake", true},
+		{"/private/notes", false},
+		{"/private/recipes/cake", true},
+		{"/menu.pdf", false},
+		{"/menu.pdf?page=2", true},
+		{"/search?q=cake", false},
+		{"/search", true},
+		{"/robots.txt", true},
+	}
+	for _, test := range tests {
+		path, query, _ := strings.Cut(test.path, "?")
+		assert.Equal(t, test.allowed, everyone.allowed(path, query), test.path)
+	}
+
+	// the groups of the product token, merged
+	ours := rules.group(DefaultUserAgent)
+	assert.True(t, ours.allowed("/private/notes", ""))
+	assert.False(t, ours.allowed("/members/list", ""))
+	assert.Equal(t, 100*time.Millisecond, ours.crawlDelay)
+	bad := rules.group("BadBot/2.0")
+	assert.False(t, bad.allowed("/recipes/cake", ""))
+
+	// groups for words of the User-Agent that are not its product token
+	// do not apply
+	rules = parseRobots(strings.NewReader("User-agent: Mozilla\nUser-agent: compatible\nDisallow: /\n\nUser-agent: INGREDIENTS\nDisallow: /members\n"))
+	ours = rules.group(DefaultUserAgent)
+	assert.True(t, ours.allowed("/recipes/cake", ""))
+	assert.False(t, ours.allowed("/members", ""))
+	assert.True(t, rules.group("Ingredients-Beta/1.0").allowed("/members", ""))
+}
+
+func TestProductToken(t *testing.T) {
+	tests := map[string]string{
+		DefaultUserAgent:  "ingredients",
+		"BadBot/2.0":      "badbot",
+		"Ingredients":     "ingredients",
+		"SomeBrowser/1.0": "somebrowser",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": "googlebot",
+	}
+	for userAgent, token := range tests {
+		assert.Equal(t, token, productToken(userAgent), userAgent)
+	}
+}
+
+func TestRobotsMatch(t *testing.T) {
+	assert.True(t, robotsMatch("/a", "/a/b"))
+	assert.False(t, robotsMatch("/a$", "/a/b"))
+	assert.True(t, robotsMatch("/a$", "/a"))
+	assert.True(t, robotsMatch("/*/b", "/a/b/c"))
+	assert.True(t, robotsMatch("/*.php$", "/a.php.php"))
+	assert.False(t, robotsMatch("/*.php$", "/a.php?x"))
+	assert.True(t, robotsMatch("*", "/anything"))
+}
+
+// robotsServer serves a robots.txt and a recipe, counting the requests
+// for the robots.txt
+func robotsServer(t *testing.T, robots string, status int) (*httptest.Server, *atomic.Int32) {
+	var robotsRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/robots.txt" {
+			robotsRequests.Add(1)
+			w.WriteHeader(status)
+			w.Write([]byte(robots))
+			return
+		}
+		w.Write([]byte("<ul><li>1 cup flour</li><li>2 eggs</li></ul>"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &robotsRequests
+}
+
+func TestRobots(t *testing.T) {
+	server, robotsRequests := robotsServer(t, testRobots, http.StatusOK)
+	cache := NewRobotsCache(time.Hour)
+	p := NewParser(WithRobots(cache), WithHeader("User-Agent", "SomeBrowser/1.0"))
+
+	_, err := p.NewFromURL(server.URL + "/recipes/cake")
+	assert.Nil(t, err)
+	_, err = p.NewFromURL(server.URL + "/private/notes")
+	var disallowed *DisallowedError
+	assert.True(t, errors.As(err, &disallowed))
+	assert.Equal(t, server.URL+"/private/notes", disallowed.URL)
+	assert.Equal(t, "SomeBrowser/1.0", disallowed.UserAgent)
+	var fetchErr *FetchError
+	assert.True(t, errors.As(err, &fetchErr))
+
+	// the cache is shared, and obeyed for the user agent of each Parser
+	other := NewParser(WithRobots(cache))
+	_, err = other.NewFromURL(server.URL + "/private/notes")
+	assert.Nil(t, err)
+	_, err = other.NewFromURL(server.URL + "/members")
+	assert.True(t, errors.As(err, &disallowed))
+	assert.Equal(t, int32(1), robotsRequests.Load())
+
+	// without WithRobots it is not fetched
+	_, err = NewFromURL(server.URL + "/private/notes")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), robotsRequests.Load())
+}
+
+func TestRobotsCrawlDelay(t *testing.T) {
+	server, robotsRequests := robotsServer(t, "User-agent: *\nCrawl-delay: 0.1\n", http.StatusOK)
+	p := NewParser(WithRobots(nil))
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.NewFromURL(server.URL + "/recipe")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 290*time.Millisecond)
+	assert.Equal(t, int32(1), robotsRequests.Load())
+}
+
+func TestRobotsStatus(t *testing.T) {
+	// a missing robots.txt allows all
+	server, _ := robotsServer(t, "User-agent: *\nDisallow: /\n", http.StatusNotFound)
+	_, err := NewParser(WithRobots(nil)).NewFromURL(server.URL + "/recipe")
+	assert.Nil(t, err)
+
+	// a server error disallows all
+	server, _ = robotsServer(t, "", http.StatusServiceUnavailable)
+	_, err = NewParser(WithRobots(nil)).NewFromURL(server.URL + "/recipe")
+	var disallowed *DisallowedError
+	assert.True(t, errors.As(err, &disallowed))
+}
+
+func TestRobotsUnavailable(t *testing.T) {
+	var robotsRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/robots.txt" && robotsRequests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeFile(w, req, fetchTestPage)
+	}))
+	t.Cleanup(server.Close)
+
+	cache := NewRobotsCache(time.Hour)
+	assert.Equal(t, robotsUnavailableTTL, cache.unavailableTTL)
+	cache.unavailableTTL = 50 * time.Millisecond
+	p := NewParser(WithRobots(cache))
+	_, err := p.NewFromURL(server.URL + "/recipe")
+	var disallowed *DisallowedError
+	assert.True(t, errors.As(err, &disallowed))
+
+	// the robots.txt is asked again soon, not after the ttl
+	time.Sleep(60 * time.Millisecond)
+	_, err = p.NewFromURL(server.URL + "/recipe")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), robotsRequests.Load())
+}
+
+// resetTransport resets the connection of the first failures requests
+// for a robots.txt
+type resetTransport struct {
+	failures int32
+	requests *atomic.Int32
+	next     http.RoundTripper
+}
+
+func (t resetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/robots.txt" && t.requests.Add(1) <= t.failures {
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	return t.next.RoundTrip(req)
+}
+
+func TestRobotsNetworkErrors(t *testing.T) {
+	fast := RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	server, _ := robotsServer(t, testRobots, http.StatusOK)
+
+	// a connection that is reset is retried
+	var requests atomic.Int32
+	client := server.Client()
+	client.Transport = resetTransport{1, &requests, client.Transport}
+	_, err := NewParser(WithRobots(nil), WithRetries(fast), WithHTTPClient(client)).NewFromURL(server.URL + "/recipe")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// a robots.txt that cannot be fetched disallows all for a short time
+	requests.Store(0)
+	client.Transport = resetTransport{10, &requests, server.Client().Transport}
+	cache := NewRobotsCache(time.Hour)
+	p := NewParser(WithRobots(cache), WithRetries(fast), WithHTTPClient(client))
+	_, err = p.NewFromURL(server.URL + "/recipe")
+	var disallowed *DisallowedError
+	assert.True(t, errors.As(err, &disallowed))
+	assert.Equal(t, int32(3), requests.Load())
+	_, err = p.NewFromURL(server.URL + "/recipe")
+	assert.True(t, errors.As(err, &disallowed))
+	assert.Equal(t, int32(3), requests.Load())
+	cache.mu.Lock()
+	expires := cache.entries[server.URL].expires
+	cache.mu.Unlock()
+	assert.WithinDuration(t, time.Now().Add(robotsUnavailableTTL), expires, time.Second)
+}
+
+func TestRobotsExpire(t *testing.T) {
+	server, robotsRequests := robotsServer(t, testRobots, http.StatusOK)
+	p := NewParser(WithRobots(NewRobotsCache(50 * time.Millisecond)))
+	_, err := p.NewFromURL(server.URL + "/recipe")
+	assert.Nil(t, err)
+	_, err = p.NewFromURL(server.URL + "/recipe")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), robotsRequests.Load())
+	time.Sleep(60 * time.Millisecond)
+	_, err = p.NewFromURL(server.URL + "/recipe")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), robotsRequests.Load())
+}
@@ -0,0 +1,136 @@
+package ingredients
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// rechosenUnits are the normalized units that are swapped for a more
+// natural one after scaling, e.g. 3 tsp becomes 1 tbsp
+var rechosenUnits = map[string]UnitSystem{
+	"tsp":        USCustomary,
+	"tbl":        USCustomary,
+	"cup":        USCustomary,
+	"ounce":      USCustomary,
+	"pound":      USCustomary,
+	"milliliter": Metric,
+	"liter":      Metric,
+	"gram":       Metric,
+	"kilogram":   Metric,
+}
+
+// Scale multiplies every ingredient by a factor and re-chooses the units
+// so that they read naturally. Whole items like eggs are rounded to whole
+// numbers, and each rounding is returned as a warning.
+func (r *Recipe) Scale(factor float64) (warnings []string, err error) {
+	if factor <= 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		err = fmt.Errorf("%w %g", ErrInvalidScale, factor)
+		return
+	}
+	p := r.getParser()
+	scale := func(m Measure) Measure {
+		m, _ = p.scaleMeasure(m, factor)
+		return m
+	}
+	for i := range r.Lines {
+		r.Lines[i].Ingredient.Measure = scale(r.Lines[i].Ingredient.Measure)
+		r.Lines[i].Ingredient.changeAlternatives(scale)
+	}
+	for i := range r.Ingredients {
+		var rounded bool
+		before := r.Ingredients[i].Measure.Amount * factor
+		r.Ingredients[i].Measure, rounded = p.scaleMeasure(r.Ingredients[i].Measure, factor)
+		r.Ingredients[i].changeAlternatives(scale)
+		if rounded {
+			warnings = append(warnings, fmt.Sprintf("rounded %s %s to %s",
+				AmountToString(before), r.Ingredients[i].Name, AmountToString(r.Ingredients[i].Measure.Amount)))
+		}
+	}
+	for i := range r.Groups {
+		for j := range r.Groups[i].Ingredients {
+			r.Groups[i].Ingredients[j].Measure = scale(r.Groups[i].Ingredients[j].Measure)
+			r.Groups[i].Ingredients[j].changeAlternatives(scale)
+		}
+	}
+	r.Metadata.Yield.Amount *= factor
+	r.Metadata.Yield.Max *= factor
+	return
+}
+
+// ScaleToServings scales the recipe from its parsed yield to a number of servings
+func (r *Recipe) ScaleToServings(servings float64) (warnings []string, err error) {
+	if r.Metadata.Yield.Amount == 0 {
+		err = ErrNoYield
+		return
+	}
+	return r.Scale(servings / r.Metadata.Yield.Amount)
+}
+
+// ScaleToIngredient scales the recipe so that it uses a given amount of one
+// ingredient, e.g. ScaleToIngredient("eggs", 3, "whole") when you have 3 eggs
+func (r *Recipe) ScaleToIngredient(name string, amount float64, unit string) (warnings []string, err error) {
+	p := r.getParser()
+	name = p.singular(strings.ToLower(strings.TrimSpace(name)))
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		unit = "whole"
+	}
+	for _, ing := range r.Ingredients {
+		if ing.Name != name {
+			continue
+		}
+		var factor float64
+		if unit == ing.Measure.Name && ing.Measure.Amount > 0 {
+			factor = amount / ing.Measure.Amount
+		} else if cups, errNormalize := p.normalizeIngredient(ing.Name, unit, amount); errNormalize == nil && ing.Measure.Cups > 0 {
+			factor = cups / ing.Measure.Cups
+		} else {
+			err = fmt.Errorf("%w %s %s with %s %s of %s", ErrIncompatibleUnits, AmountToString(amount), unit, AmountToString(ing.Measure.Amount), ing.Measure.Name, name)
+			return
+		}
+		return r.Scale(factor)
+	}
+	err = fmt.Errorf("%w '%s'", ErrNotFound, name)
+	return
+}
+
+// scaleMeasure multiplies a measure and reports whether a whole item was rounded
+func (p *Parser) scaleMeasure(m Measure, factor float64) (scaled Measure, rounded bool) {
+	scaled = m
+	scaled.Amount *= factor
+	scaled.Min *= factor
+	scaled.Max *= factor
+	scaled.Cups *= factor
+	scaled.Weight *= factor
+	scaled.Parts = nil
+	if m.Package != nil {
+		p := *m.Package
+		p.Count *= factor
+		scaled.Package = &p
+		return
+	}
+
+	if m.Name == "whole" {
+		// a count of items, unlike "1/2 onion", stays a count
+		if m.Amount != math.Trunc(m.Amount) || scaled.Amount == math.Round(scaled.Amount) {
+			return
+		}
+		amount := math.Max(1, math.Round(scaled.Amount))
+		ratio := amount / scaled.Amount
+		scaled.Amount = amount
+		scaled.Cups *= ratio
+		scaled.Weight *= ratio
+		if scaled.IsRange() {
+			scaled.Min = math.Max(1, math.Round(scaled.Min))
+			scaled.Max = math.Max(scaled.Min, math.Round(scaled.Max))
+		}
+		rounded = true
+		return
+	}
+
+	if system, ok := rechosenUnits[p.corpus.Measures[m.Name]]; ok && scaled.Cups > 0 {
+		scaled = p.convertMeasure(scaled, "", system)
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package ingredients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseLines(t *testing.T, lines string) *Recipe {
+	r := &Recipe{FileName: "lines"}
+	_, r.Lines = scoreLines(strings.Split(lines, "\n"))
+	assert.Nil(t, r.parseRecipe(false))
+	return r
+}
+
+func TestScale(t *testing.T) {
+	r := parseLines(t, `1 teaspoon vanilla
+1/2 cup sugar
+2 whole eggs
+1/2 whole onion
+200 g flour`)
+	warnings, err := r.Scale(3)
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, `1 tablespoon vanilla
+1 1/2 cups sugar
+6 whole eggs
+1 1/2 whole onions
+600 g flour`, strings.TrimSpace(r.IngredientList().String()))
+
+	r = parseLines(t, `1 teaspoon vanilla
+1/2 cup sugar
+2 whole eggs
+1 kg flour`)
+	warnings, err = r.Scale(0.75)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"rounded 1 1/2 egg to 2"}, warnings)
+	assert.Equal(t, 2.0, r.Ingredients[2].Measure.Amount)
+	assert.Equal(t, "g", r.Ingredients[3].Measure.Name)
+	assert.Equal(t, 750.0, r.Ingredients[3].Measure.Amount)
+
+	_, err = r.Scale(0)
+	assert.NotNil(t, err)
+}
+
+func TestScaleToServings(t *testing.T) {
+	r := parseLines(t, "1 cup flour\n2 whole eggs")
+	_, err := r.ScaleToServings(8)
+	assert.NotNil(t, err)
+
+	r.Metadata.Yield = ParseYield("4 servings")
+	_, err = r.ScaleToServings(8)
+	assert.Nil(t, err)
+	assert.Equal(t, 8.0, r.Metadata.Yield.Amount)
+	assert.Equal(t, 2.0, r.Ingredients[0].Measure.Amount)
+	assert.Equal(t, 4.0, r.Ingredients[1].Measure.Amount)
+}
+
+func TestScaleToIngredient(t *testing.T) {
+	r := parseLines(t, "1 cup flour\n2 whole eggs\n24 teaspoons sugar")
+	warnings, err := r.ScaleToIngredient("eggs", 3, "")
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, "1 1/2 cups flour", AmountToString(r.Ingredients[0].Measure.Amount)+" "+r.Ingredients[0].Measure.Name+" flour")
+	assert.Equal(t, "3/4 cup sugar", AmountToString(r.Ingredients[2].Measure.Amount)+" "+r.Ingredients[2].Measure.Name+" sugar")
+
+	_, err = r.ScaleToIngredient("flour", 3, "cups")
+	assert.Nil(t, err)
+	assert.InDelta(t, 6.0, r.Ingredients[1].Measure.Amount, 1e-9)
+
+	_, err = r.ScaleToIngredient("butter", 1, "cup")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,205 @@
+package ingredients
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Trie is an Aho–Corasick automaton that finds the corpus entries in a
+// line in one pass. Entries only match whole words, between spaces or the
+// ends of the line. The nodes and their edges are kept in flat slices,
+// with the edges of a node sorted by rune for a binary search.
+type Trie struct {
+	nodes    []trieNode
+	edges    []trieEdge
+	patterns []triePattern
+	// root has the children of the root for ASCII runes, the most common
+	// transitions
+	root [utf8.RuneSelf]int32
+}
+
+type trieNode struct {
+	firstEdge int32
+	numEdges  int32
+	// fail is the node of the longest proper suffix that is also a prefix
+	fail int32
+	// output is the pattern that ends at the node, or -1
+	output int32
+	// dict is the nearest node along the failure links with an output, or -1
+	dict int32
+}
+
+type trieEdge struct {
+	r    rune
+	next int32
+}
+
+type triePattern struct {
+	word  string
+	runes int
+	bytes int
+}
+
+// newTrie builds the automaton from a list of patterns, ignoring any
+// space padding around them
+func newTrie(patterns []string) *Trie {
+	type buildNode struct {
+		children map[rune]int32
+		output   int32
+	}
+	build := []buildNode{{children: make(map[rune]int32), output: -1}}
+	t := &Trie{}
+	for _, pattern := range patterns {
+		word := strings.TrimSpace(pattern)
+		if word == "" {
+			continue
+		}
+		node := int32(0)
+		for _, r := range word {
+			child, ok := build[node].children[r]
+			if !ok {
+				child = int32(len(build))
+				build = append(build, buildNode{children: make(map[rune]int32), output: -1})
+				build[node].children[r] = child
+			}
+			node = child
+		}
+		if build[node].output < 0 {
+			build[node].output = int32(len(t.patterns))
+			t.patterns = append(t.patterns, triePattern{word: word, runes: utf8.RuneCountInString(word), bytes: len(word)})
+		}
+	}
+
+	// lay out the edges of every node sorted by rune
+	t.nodes = make([]trieNode, len(build))
+	for i, b := range build {
+		runes := make([]rune, 0, len(b.children))
+		for r := range b.children {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+		t.nodes[i] = trieNode{firstEdge: int32(len(t.edges)), numEdges: int32(len(runes)), output: b.output, dict: -1}
+		for _, r := range runes {
+			t.edges = append(t.edges, trieEdge{r: r, next: b.children[r]})
+			if i == 0 && r < utf8.RuneSelf {
+				t.root[r] = b.children[r]
+			}
+		}
+	}
+
+	// link every node to its failure and dictionary nodes breadth first,
+	// starting with the children of the root which fail to the root
+	queue := make([]int32, 0, len(t.nodes))
+	for _, e := range t.nodeEdges(0) {
+		queue = append(queue, e.next)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, e := range t.nodeEdges(node) {
+			fail := t.next(t.nodes[node].fail, e.r)
+			t.nodes[e.next].fail = fail
+			if t.nodes[fail].output >= 0 {
+				t.nodes[e.next].dict = fail
+			} else {
+				t.nodes[e.next].dict = t.nodes[fail].dict
+			}
+			queue = append(queue, e.next)
+		}
+	}
+	return t
+}
+
+func (t *Trie) nodeEdges(node int32) []trieEdge {
+	n := t.nodes[node]
+	return t.edges[n.firstEdge : n.firstEdge+n.numEdges]
+}
+
+// next follows the edge for a rune, falling back along the failure links
+func (t *Trie) next(state int32, r rune) int32 {
+	for {
+		if state == 0 {
+			if r < utf8.RuneSelf {
+				return t.root[r]
+			}
+		}
+		edges := t.nodeEdges(state)
+		lo, hi := 0, len(edges)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if edges[mid].r < r {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		if lo < len(edges) && edges[lo].r == r {
+			return edges[lo].next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = t.nodes[state].fail
+	}
+}
+
+// findAll returns the whole-word matches in the text with the position of
+// the rune before each word, which is the space that pads it. From left to
+// right, the longest match at each position wins and the matches do not
+// overlap, not even in the spaces around them.
+func (t *Trie) findAll(text string) (matches []WordPosition) {
+	state := int32(0)
+	runeEnd := 0
+	for end := 0; end < len(text); {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+		runeEnd++
+		state = t.next(state, r)
+		node := state
+		if t.nodes[node].output < 0 {
+			node = t.nodes[node].dict
+		}
+		for ; node >= 0; node = t.nodes[node].dict {
+			pattern := t.patterns[t.nodes[node].output]
+			start := end - pattern.bytes
+			if (start > 0 && text[start-1] != ' ') || (end < len(text) && text[end] != ' ') {
+				continue
+			}
+			matches = insertMatch(matches, WordPosition{Word: pattern.word, Position: runeEnd - pattern.runes - 1})
+		}
+	}
+
+	// keep the longest match at each position that does not overlap
+	// the one before it, counting the spaces around the words
+	kept := matches[:0]
+	last := -2
+	for _, match := range matches {
+		if match.Position > last {
+			kept = append(kept, match)
+			last = match.Position + utf8.RuneCountInString(match.Word) + 1
+		}
+	}
+	return kept
+}
+
+// insertMatch adds a match to the matches sorted by position. Matches are
+// found by where they end, so a match at the same position is longer and
+// replaces the one there.
+func insertMatch(matches []WordPosition, match WordPosition) []WordPosition {
+	i := len(matches)
+	for i > 0 && matches[i-1].Position > match.Position {
+		i--
+	}
+	if i > 0 && matches[i-1].Position == match.Position {
+		matches[i-1] = match
+		return matches
+	}
+	if matches == nil {
+		matches = make([]WordPosition, 0, 4)
+	}
+	matches = append(matches, WordPosition{})
+	copy(matches[i+1:], matches[i:])
+	matches[i] = match
+	return matches
+}
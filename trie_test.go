@@ -0,0 +1,110 @@
+package ingredients
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrie(t *testing.T) {
+	trie := newTrie([]string{"salt", "sea salt", "pepper", "red pepper flake", "ice", "crème fraîche"})
+	tests := []struct {
+		text    string
+		matches []WordPosition
+	}{
+		{" 1 tsp sea salt ", []WordPosition{{"sea salt", 6}}},
+		{" salt and pepper ", []WordPosition{{"salt", 0}, {"pepper", 9}}},
+		// whole words only
+		{" spice rice ", nil},
+		{" red pepper flakes ", []WordPosition{{"pepper", 4}}},
+		// matches do not share the spaces around them
+		{" salt pepper ", []WordPosition{{"salt", 0}}},
+		{" salt  pepper ", []WordPosition{{"salt", 0}, {"pepper", 6}}},
+		// positions count runes
+		{" 1 cup crème fraîche and salt ", []WordPosition{{"crème fraîche", 6}, {"salt", 24}}},
+		// the ends of the text are boundaries too
+		{"salt", []WordPosition{{"salt", -1}}},
+		{"", nil},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.matches, trie.findAll(test.text), test.text)
+	}
+}
+
+// naiveFindAll is a reference implementation, which walks the padded
+// patterns from every position of the text
+func naiveFindAll(patterns []string, text string) (matches []WordPosition) {
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		longest := ""
+		for _, pattern := range patterns {
+			padded := " " + pattern + " "
+			if strings.HasPrefix(string(runes[i:]), padded) && len(padded) > len(longest) {
+				longest = padded
+			}
+		}
+		if longest != "" {
+			matches = append(matches, WordPosition{Word: strings.TrimSpace(longest), Position: i})
+			i += len([]rune(longest)) - 1
+		}
+	}
+	return
+}
+
+func TestTrieMatchesReference(t *testing.T) {
+	patterns := []string{"flour", "all purpose flour", "purpose", "chocolate", "chocolate chips", "chips", "vanilla", "vanilla extract", "extract", "1", "1/2", "2", "cup", "cups"}
+	trie := newTrie(patterns)
+	for _, line := range tableLines() {
+		assert.Equal(t, naiveFindAll(patterns, line), trie.findAll(line), line)
+	}
+}
+
+// tableLines returns the sanitized lines of the pages in the table tests
+func tableLines() (lines []string) {
+	for _, t0 := range ts {
+		fileToGet := strings.TrimPrefix(t0.URL, "https://")
+		if strings.HasSuffix(fileToGet, "/") {
+			fileToGet += "index.html"
+		}
+		r, err := NewFromFile(path.Join("testing", "sites", fileToGet))
+		if err != nil {
+			continue
+		}
+		for _, line := range r.Lines {
+			lines = append(lines, line.Line)
+		}
+	}
+	sort.Strings(lines)
+	return
+}
+
+func BenchmarkTrieTable(b *testing.B) {
+	lines := tableLines()
+	for _, trie := range []struct {
+		name string
+		trie *Trie
+	}{
+		{"ingredients", defaultParser.ingredientsTrie},
+		{"measures", defaultParser.measuresTrie},
+		{"numbers", defaultParser.numbersTrie},
+	} {
+		b.Run(trie.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, line := range lines {
+					trie.trie.findAll(line)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNewTrie(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		newTrie(defaultParser.corpus.Ingredients)
+	}
+}
@@ -1,11 +1,9 @@
 package ingredients
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -13,100 +11,13 @@ import (
 )
 
 var (
-	reParentheses  = regexp.MustCompile(`(?s)\((.*)\)`)
-	reNonAlphaNum  = regexp.MustCompile("[^a-zA-Z0-9/.]+")
+	reParentheses   = regexp.MustCompile(`\([^()]*\)`)
+	reNonAlphaNum   = regexp.MustCompile("[^a-zA-Z0-9/.]+")
 	reNumberAtStart = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?|\d+\s+\d+/\d+)`)
-)
-
-// Trie node for efficient pattern matching
-type trieNode struct {
-	children map[rune]*trieNode
-	isEnd    bool
-	value    string
-}
-
-// Trie for fast multi-pattern matching
-type Trie struct {
-	root *trieNode
-}
-
-// newTrie creates a new trie from a list of patterns
-func newTrie(patterns []string) *Trie {
-	t := &Trie{root: &trieNode{children: make(map[rune]*trieNode)}}
-	for _, pattern := range patterns {
-		t.insert(pattern)
-	}
-	return t
-}
-
-// insert adds a pattern to the trie
-func (t *Trie) insert(pattern string) {
-	node := t.root
-	for _, ch := range pattern {
-		if node.children[ch] == nil {
-			node.children[ch] = &trieNode{children: make(map[rune]*trieNode)}
-		}
-		node = node.children[ch]
-	}
-	node.isEnd = true
-	node.value = pattern
-}
-
-// findAll returns all pattern matches in the text with their positions
-// Implements greedy longest-match strategy like the original algorithm
-func (t *Trie) findAll(text string) []WordPosition {
-	var matches []WordPosition
-	runes := []rune(text)
-	used := make([]bool, len(runes)) // Track which positions are already matched
-
-	// Try to find matches starting at each position
-	for i := 0; i < len(runes); i++ {
-		if used[i] {
-			continue
-		}
-
-		// Find longest match starting at position i
-		node := t.root
-		longestMatch := ""
-		longestEnd := -1
-
-		for j := i; j < len(runes); j++ {
-			ch := runes[j]
-			if node.children[ch] == nil {
-				break
-			}
-			node = node.children[ch]
-			if node.isEnd {
-				longestMatch = node.value
-				longestEnd = j
-			}
-		}
-
-		// If we found a match, record it and mark positions as used
-		if longestMatch != "" {
-			matches = append(matches, WordPosition{
-				Word:     strings.TrimSpace(longestMatch),
-				Position: i,
-			})
-			// Mark these positions as used
-			for j := i; j <= longestEnd; j++ {
-				used[j] = true
-			}
-		}
-	}
-
-	// Sort by position
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Position < matches[j].Position
-	})
-
-	return matches
-}
-
-var (
-	ingredientsTrie *Trie
-	measuresTrie    *Trie
-	numbersTrie     *Trie
+	reRangeAtStart  = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?)\s+(?:to|or)\s+(\d+(?:\.\d+)?)\s`)
+	reMixedNumber   = regexp.MustCompile(`(^|[^\d/])(\d+)\s*-\s*(\d+/\d+)`)
+	reRangeDash     = regexp.MustCompile(`(\d)\s*[-–—]\s*(\d)`)
+	reApproximate   = regexp.MustCompile(`(?i)^\s*[*-]?\s*(about|approximately|approx\.?|roughly|around|~)\s*[\d½¼¾⅛⅜⅝⅞⅔⅓]`)
 )
 
 var wordNumbers = map[string]float64{
@@ -121,13 +32,6 @@ var wordNumbers = map[string]float64{
 	"half": 0.5, "quarter": 0.25,
 }
 
-// init builds the tries from corpus data for fast pattern matching
-func init() {
-	ingredientsTrie = newTrie(corpusIngredients)
-	measuresTrie = newTrie(corpusMeasures)
-	numbersTrie = newTrie(corpusNumbers)
-}
-
 // ConvertStringToNumber converts string numbers (including fractions and word forms) to float64
 func ConvertStringToNumber(s string) float64 {
 	// Handle unicode fractions
@@ -138,6 +42,8 @@ func ConvertStringToNumber(s string) float64 {
 		return 0.25
 	case "¾":
 		return 0.75
+	case "⅙":
+		return 1.0 / 6
 	case "⅛":
 		return 1.0 / 8
 	case "⅜":
@@ -162,40 +68,50 @@ func ConvertStringToNumber(s string) float64 {
 	return v
 }
 
-func AmountToString(amount float64) string {
+// AmountToString renders an amount as a whole number and fraction, e.g. "1 1/2".
+// If an upper bound different from the amount is given, the range is
+// rendered as "2–3".
+func AmountToString(amount float64, upTo ...float64) string {
+	if len(upTo) > 0 && upTo[0] != amount {
+		return AmountToString(amount) + "–" + AmountToString(upTo[0])
+	}
 	r, _ := parseDecimal(fmt.Sprintf("%2.10f", amount))
 	rationalFraction := float64(r.n) / float64(r.d)
 	if rationalFraction > 0 {
 		bestFractionDiff := 1e9
-		bestFraction := 0.0
-		var fractions = map[float64]string{
-			0:       "",
-			1:       "",
-			1.0 / 2: "1/2",
-			1.0 / 3: "1/3",
-			2.0 / 3: "2/3",
-			1.0 / 6: "1/6",
-			1.0 / 8: "1/8",
-			3.0 / 8: "3/8",
-			5.0 / 8: "5/8",
-			7.0 / 8: "7/8",
-			1.0 / 4: "1/4",
-			3.0 / 4: "3/4",
+		bestFraction := ""
+		// in increasing order, so that a tie goes to the smaller fraction
+		var fractions = []struct {
+			value float64
+			s     string
+		}{
+			{0, ""},
+			{1.0 / 8, "1/8"},
+			{1.0 / 6, "1/6"},
+			{1.0 / 4, "1/4"},
+			{1.0 / 3, "1/3"},
+			{3.0 / 8, "3/8"},
+			{1.0 / 2, "1/2"},
+			{5.0 / 8, "5/8"},
+			{2.0 / 3, "2/3"},
+			{3.0 / 4, "3/4"},
+			{7.0 / 8, "7/8"},
+			{1, ""},
 		}
-		for f := range fractions {
-			currentDiff := math.Abs(f - rationalFraction)
+		for _, f := range fractions {
+			currentDiff := math.Abs(f.value - rationalFraction)
 			if currentDiff < bestFractionDiff {
-				bestFraction = f
+				bestFraction = f.s
 				bestFractionDiff = currentDiff
 			}
 		}
-		if fractions[bestFraction] == "" {
+		if bestFraction == "" {
 			return strconv.FormatInt(int64(math.Round(amount)), 10)
 		}
 		if r.i > 0 {
-			return strconv.FormatInt(r.i, 10) + " " + fractions[bestFraction]
+			return strconv.FormatInt(r.i, 10) + " " + bestFraction
 		} else {
-			return fractions[bestFraction]
+			return bestFraction
 		}
 	}
 	return strconv.FormatInt(r.i, 10)
@@ -261,17 +177,17 @@ func parseDecimal(s string) (r rational, err error) {
 
 // GetIngredientsInString returns the word positions of the ingredients
 func GetIngredientsInString(s string) (wordPositions []WordPosition) {
-	return ingredientsTrie.findAll(s)
+	return defaultParser.ingredientsTrie.findAll(s)
 }
 
 // GetNumbersInString returns the word positions of the numbers in the ingredient string
 func GetNumbersInString(s string) (wordPositions []WordPosition) {
-	return numbersTrie.findAll(s)
+	return defaultParser.numbersTrie.findAll(s)
 }
 
 // GetMeasuresInString returns the word positions of the measures in a ingredient string
 func GetMeasuresInString(s string) (wordPositions []WordPosition) {
-	return measuresTrie.findAll(s)
+	return defaultParser.measuresTrie.findAll(s)
 }
 
 // WordPosition shows a word and its position
@@ -316,8 +232,6 @@ var sanitizeReplacer = strings.NewReplacer(
 	"⁄", "/",
 	" / ", "/",
 	"butter milk", "buttermilk",
-	"bicarbonate of soda", "baking soda",
-	"soda bicarbonate", "baking soda",
 	" one ", " 1 ",
 )
 
@@ -350,6 +264,10 @@ func SanitizeLine(s string) string {
 		}
 	}
 
+	// Keep ranges like "2-3" apart as "2 to 3", but read "1-1/2" as "1 1/2"
+	s = reMixedNumber.ReplaceAllString(s, "$1$2 $3")
+	s = reRangeDash.ReplaceAllString(s, "$1 to $2")
+
 	// Remove non-alphanumeric characters (preserving / and .)
 	s = reNonAlphaNum.ReplaceAllString(s, " ")
 
@@ -365,9 +283,10 @@ func SanitizeLine(s string) string {
 }
 
 var gramConversions = map[string]float64{
-	"ounce": 28.3495,
-	"gram":  1,
-	"pound": 453.592,
+	"ounce":    28.3495,
+	"gram":     1,
+	"pound":    453.592,
+	"kilogram": 1000,
 }
 
 var conversionToCup = map[string]float64{
@@ -378,7 +297,11 @@ var conversionToCup = map[string]float64{
 	"quart":      4.0,
 	"gallon":     16.0,
 	"milliliter": 0.00423,
+	"liter":      4.22675,
 	"can":        1.75,
+
+	"imperial fluid ounce": millilitersPerImperialOz / millilitersPerCup,
+	"imperial pint":        millilitersPerImperialPt / millilitersPerCup,
 }
 var ingredientToCups = map[string]float64{
 	"eggs":      0.125,
@@ -399,6 +322,29 @@ var ingredientToCups = map[string]float64{
 	"butter":    0.5,
 }
 
+// ingredientToGrams is the weight of one whole item in grams
+var ingredientToGrams = map[string]float64{
+	"egg":       50,
+	"egg yolk":  18,
+	"egg white": 33,
+	"lime":      67,
+	"lemon":     84,
+	"orange":    131,
+	"banana":    118,
+	"apple":     182,
+	"avocado":   150,
+	"potato":    213,
+	"tomato":    123,
+	"onion":     110,
+	"shallot":   25,
+	"carrot":    61,
+	"celery":    40,
+	"garlic":    3,
+	"clove":     3,
+	"butter":    113,
+	"chicken":   1500,
+}
+
 func cupsToOther(cups float64, ingredient string) (amount float64, measure string) {
 	if _, ok := ingredientToCups[ingredient]; ok {
 		measure = "whole"
@@ -423,11 +369,11 @@ func cupsToOther(cups float64, ingredient string) (amount float64, measure strin
 }
 
 // normalizeIngredient will try to normalize the ingredient to 1 cup
-func normalizeIngredient(ingredient, measure string, amount float64) (cups float64, err error) {
+func (p *Parser) normalizeIngredient(ingredient, measure string, amount float64) (cups float64, err error) {
 	// convert measure to standard measure
-	newMeasure, ok := corpusMeasuresMap[measure]
+	newMeasure, ok := p.corpus.Measures[measure]
 	if !ok && measure != "whole" {
-		err = fmt.Errorf("could not find '%s'", measure)
+		err = fmt.Errorf("%w '%s'", ErrNotFound, measure)
 		return
 	}
 	measure = newMeasure
@@ -439,31 +385,67 @@ func normalizeIngredient(ingredient, measure string, amount float64) (cups float
 		cups = float64(amount) * conversionToCup[measure]
 	} else if _, ok := gramConversions[measure]; ok {
 		// check if it has a standard weight measurement
-		var density float64
-		density, ok = densities[ingredient]
+		density, ok := p.density(ingredient)
 		if !ok {
 			density = 200 // grams / cup
 		}
 		cups = amount * gramConversions[measure] / density
 	} else {
-		if _, ok := fruitMap[ingredient]; ok {
+		id := p.canonical(ingredient)
+		if _, ok := p.fruits[id]; ok {
 			cups = 1 * amount
-		} else if _, ok := vegetableMap[ingredient]; ok {
+		} else if _, ok := p.vegetables[id]; ok {
 			cups = 1 * amount
-		} else if _, ok := herbMap[ingredient]; ok {
+		} else if _, ok := p.herbs[id]; ok {
 			cups = 0.0208333 * amount
 		} else {
-			err = errors.New("could not convert weight or volume")
+			err = ErrNotConvertible
 		}
 	}
 	return
 }
 
+// weighIngredient determines the grams of an ingredient. Weights are exact,
+// volumes go through the density of the ingredient and whole items through
+// their weight per item. Anything else is estimated at 200 grams per cup.
+func (p *Parser) weighIngredient(ingredient, measure string, amount, cups float64) (grams float64, source WeightSource, err error) {
+	newMeasure, ok := p.corpus.Measures[measure]
+	if !ok && measure != "whole" {
+		err = fmt.Errorf("%w '%s'", ErrNotFound, measure)
+		return
+	}
+	if _, ok := gramConversions[newMeasure]; ok {
+		grams = amount * gramConversions[newMeasure]
+		source = WeightExact
+		return
+	}
+	if _, ok := ingredientToGrams[ingredient]; ok && newMeasure == "" {
+		grams = amount * ingredientToGrams[ingredient]
+		source = WeightPerItem
+		return
+	}
+	if cups == 0 {
+		err = fmt.Errorf("%w '%s'", ErrNotWeighable, ingredient)
+		return
+	}
+	if density, ok := p.density(ingredient); ok {
+		grams = cups * density
+		source = WeightDensity
+	} else {
+		grams = cups * 200
+		source = WeightEstimated
+	}
+	return
+}
+
+// determineMeasurementsFromCups chooses cups from a quarter cup, tablespoons
+// from one tablespoon and teaspoons for anything smaller. The thresholds
+// allow for the rounding in conversionToCup, so 3 tsp is 1 tbsp.
 func determineMeasurementsFromCups(cups float64) (amount float64, measure string, amountString string, err error) {
-	if cups > 0.125 {
+	if cups >= 0.25-1e-4 {
 		amount = cups
 		measure = "cup"
-	} else if cups > 0.020833*3 {
+	} else if cups >= 0.0625-1e-4 {
 		amount = cups * 16
 		measure = "tablespoon"
 	} else {
@@ -479,3 +461,13 @@ func determineMeasurementsFromCups(cups float64) (amount float64, measure string
 	}
 	return
 }
+
+// isRangeSeparator reports whether the runes between two numbers join
+// them as a range, e.g. "2 to 3" or "1 or 2"
+func isRangeSeparator(runes []rune, start, end int) bool {
+	if start < 0 || end > len(runes) || start >= end {
+		return false
+	}
+	between := strings.TrimSpace(string(runes[start:end]))
+	return between == "to" || between == "or"
+}
@@ -10,3 +10,18 @@ func TestGetIngredientsInString(t *testing.T) {
 	g := GetIngredientsInString("* 1 1/2 cups (255g) chocolate chips (semi-sweet or milk)")
 	assert.Equal(t, "chocolate chips", g[0].Word)
 }
+
+func TestAmountToString(t *testing.T) {
+	assert.Equal(t, "1 1/2", AmountToString(1.5))
+	assert.Equal(t, "3/4", AmountToString(0.75))
+	assert.Equal(t, "2–3", AmountToString(2, 3))
+	assert.Equal(t, "1/2–1", AmountToString(0.5, 1))
+	assert.Equal(t, "2", AmountToString(2, 2))
+}
+
+func TestSanitizeLineRanges(t *testing.T) {
+	assert.Equal(t, " 2 to 3 cloves garlic ", SanitizeLine("2-3 cloves garlic"))
+	assert.Equal(t, " 1 to 2 tbsp butter ", SanitizeLine("1–2 tbsp butter"))
+	assert.Equal(t, " 1  ½  cups flour ", SanitizeLine("1-1/2 cups flour"))
+	assert.Equal(t, "  ½  to  ¾  cup milk ", SanitizeLine("½-¾ cup milk"))
+}
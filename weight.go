@@ -0,0 +1,79 @@
+package ingredients
+
+import "math"
+
+// WeightSource says how Measure.Weight was determined
+type WeightSource string
+
+const (
+	// WeightExact is a weight given in the recipe, e.g. "200 g" or "1 lb"
+	WeightExact WeightSource = "exact"
+	// WeightPerItem uses the weight of one whole item, e.g. 50 g per egg
+	WeightPerItem WeightSource = "per_item"
+	// WeightDensity converts a volume with the density of the ingredient
+	WeightDensity WeightSource = "density"
+	// WeightEstimated converts a volume assuming 200 grams per cup
+	WeightEstimated WeightSource = "estimated"
+)
+
+// weightSourceRank orders the sources from most to least precise
+var weightSourceRank = map[WeightSource]int{
+	WeightExact:     1,
+	WeightPerItem:   2,
+	WeightDensity:   3,
+	WeightEstimated: 4,
+}
+
+// leastPrecise returns the less precise of two weight sources, which is
+// empty when either weight is unknown
+func leastPrecise(a, b WeightSource) WeightSource {
+	if a == "" || b == "" {
+		return ""
+	}
+	if weightSourceRank[b] > weightSourceRank[a] {
+		return b
+	}
+	return a
+}
+
+// weigh sets the weight in grams of the ingredient. The parts of a
+// compound measure are weighed separately and added up.
+func (ing *Ingredient) weigh(p *Parser) (err error) {
+	ing.Measure.Weight, ing.Measure.WeightSource = 0, ""
+	parts := ing.Measure.Parts
+	if len(parts) < 2 {
+		parts = []Measure{ing.Measure}
+	}
+	for _, part := range parts {
+		grams, source, errWeigh := p.weighIngredient(ing.Name, part.Name, part.Amount, part.Cups)
+		if errWeigh != nil {
+			ing.Measure.Weight, ing.Measure.WeightSource = 0, ""
+			return errWeigh
+		}
+		ing.Measure.Weight += grams
+		if ing.Measure.WeightSource == "" {
+			ing.Measure.WeightSource = source
+		}
+		ing.Measure.WeightSource = leastPrecise(ing.Measure.WeightSource, source)
+	}
+	return
+}
+
+// WeightList returns the consolidated ingredients with every measure in
+// grams. Ingredients that could not be weighed keep their measure.
+func (r *Recipe) WeightList() (ingredientList IngredientList) {
+	ingredientList = IngredientList{Ingredients: make([]Ingredient, len(r.Ingredients)), parser: r.parser}
+	for i, ing := range r.Ingredients {
+		if ing.Measure.Weight > 0 {
+			ing.Measure = Measure{
+				Amount:       math.Round(ing.Measure.Weight),
+				Name:         "grams",
+				Cups:         ing.Measure.Cups,
+				Weight:       ing.Measure.Weight,
+				WeightSource: ing.Measure.WeightSource,
+			}
+		}
+		ingredientList.Ingredients[i] = ing
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package ingredients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeights(t *testing.T) {
+	tests := []struct {
+		input  string
+		weight float64
+		source WeightSource
+	}{
+		{"200 g sugar", 200, WeightExact},
+		{"1 lb ground beef", 453.592, WeightExact},
+		{"1 (12-ounce) bag chocolate chips", 12 * 28.3495, WeightExact},
+		{"1 cup flour", densities["flour"], WeightDensity},
+		{"2 whole eggs", 100, WeightPerItem},
+		{"1 cup plus 2 tablespoons flour", 1.125 * densities["flour"], WeightDensity},
+		{"1 cup chopped xanthan gum", 200, WeightEstimated},
+	}
+	for _, test := range tests {
+		r := &Recipe{FileName: "lines", FileContent: test.input}
+		_, lineInfo := scoreLine(test.input)
+		r.Lines = []LineInfo{lineInfo}
+		assert.Nil(t, r.parseRecipe(false))
+		if !assert.Equal(t, 1, len(r.Lines), test.input) {
+			continue
+		}
+		m := r.Lines[0].Ingredient.Measure
+		assert.InDelta(t, test.weight, m.Weight, 1e-6, test.input)
+		assert.Equal(t, test.source, m.WeightSource, test.input)
+	}
+}
+
+func TestWeightList(t *testing.T) {
+	r := &Recipe{FileName: "lines"}
+	_, r.Lines = scoreLines([]string{"1 cup flour", "100 g sugar", "2 whole eggs", "100 g flour"})
+	assert.Nil(t, r.parseRecipe(false))
+	assert.Equal(t, strings.TrimSpace(`214 grams flour
+100 grams sugar
+100 grams egg`), strings.TrimSpace(r.WeightList().String()))
+	assert.Equal(t, WeightDensity, r.Ingredients[0].Measure.WeightSource)
+	assert.Equal(t, WeightExact, r.Ingredients[1].Measure.WeightSource)
+}
+
+func TestLeastPrecise(t *testing.T) {
+	assert.Equal(t, WeightDensity, leastPrecise(WeightExact, WeightDensity))
+	assert.Equal(t, WeightEstimated, leastPrecise(WeightEstimated, WeightPerItem))
+	assert.Equal(t, WeightSource(""), leastPrecise(WeightExact, ""))
+}